@@ -1,745 +1,994 @@
-package shrimpygo
-
-import (
-	"crypto/hmac"
-	"crypto/sha256"
-	b64 "encoding/base64"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strconv"
-	"strings"
-	"time"
-)
-
-const (
-	//GET so I don't have to type quotes all the time
-	GET = "GET"
-	//POST so I don't have to type quotes all the time
-	POST = "POST"
-	//DELETE so I don't have to type quotes all the time
-	DELETE = "DELETE"
-)
-
-var nonce int64
-
-/*
-
-	START PUBLIC FUNCTIONS
-
-*/
-
-//GetSupportedExchanges returns a list of exchange objects for all exchanges that shrimpy supports
-func (client *Client) GetSupportedExchanges() SupportedExchanges {
-	r := new(SupportedExchanges)
-	jsonStringReturn := httpDo(GET, "", "/v1/list_exchanges", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetExchangeAssets returns a list of exchange assets for a particular exchange
-func (client *Client) GetExchangeAssets(exchangeName string) Assets {
-	r := new(Assets)
-	jsonStringReturn := httpDo(GET, "", "/v1/exchanges/"+exchangeName+"/assets", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetExchangePairs returns a list of exchange pairs for a particular exchange
-func (client *Client) GetExchangePairs(exchangeName string) Pairs {
-	r := new(Pairs)
-	jsonStringReturn := httpDo(GET, "", "/v1/exchanges/"+exchangeName+"/trading_pairs", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END PUBLIC FUNCTIONS
-	START MARKET FUNCTIONS
-
-*/
-
-//GetExchangeTickers returns a list of exchange tickers for all exchanges that shrimpy supports
-func (client *Client) GetExchangeTickers(exchangeName string) Tickers {
-	r := new(Tickers)
-	jsonStringReturn := httpDo(GET, "", "/v1/exchanges/"+exchangeName+"/ticker", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetCandleStickData returns all candlesticks for this exchange
-func (client *Client) GetCandleStickData(exchangeName string, quoteTradingSymbol string, baseTradingSymbol string, interval string) CandleSticks {
-	r := new(CandleSticks)
-
-	params := "?quoteTradingSymbol=" + quoteTradingSymbol + "&baseTradingSymbol=" + baseTradingSymbol + "&interval=" + interval
-
-	jsonStringReturn := httpDo(GET, params, "/v1/exchanges/"+exchangeName+"/candles", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetOrderBooks returns orderbooks for all exchanges in the slice, basesymbol, quotesymbol and limit are all inputs
-func (client *Client) GetOrderBooks(sliceExchanges []string, limit, quoteSymbol, baseSymbol string) ExchangeOrders {
-
-	r := new(ExchangeOrders)
-
-	exchange := "all"
-	sLimit := "&limit=10"
-	qSymbol := ""
-	bSymbol := ""
-
-	if len(sliceExchanges) > 0 {
-		exchange = strings.Join(sliceExchanges, ",")
-	}
-
-	if limit != "" {
-		sLimit = "&limit=" + limit
-	}
-
-	if quoteSymbol != "" {
-		qSymbol = "&quoteSymbol=" + quoteSymbol
-	}
-
-	if baseSymbol != "" {
-		bSymbol = "&baseSymbol=" + baseSymbol
-	}
-
-	params := "?exchange=" + exchange + sLimit + qSymbol + bSymbol
-
-	jsonStringReturn := httpDo(GET, params, "/v1/orderbooks", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END MARKET FUNCTIONS
-	START USER FUNCTIONS - NOTE: You must have the user permission enabled on your Master API key to use these endpoints
-
-*/
-
-//GetUserList returns all users associated with your masterAPI key
-func (client *Client) GetUserList() UsersList {
-	r := new(UsersList)
-
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetSingleUserList returns a single user based on ID
-func (client *Client) GetSingleUserList(userID string) SingleUser {
-	r := new(SingleUser)
-
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//CreateUser associates a new user with your account. Returns the ID of that user
-func (client *Client) CreateUser(userName string) UserID {
-	r := new(UserID)
-	params := ""
-	finalBody := ""
-
-	if userName != "" {
-		var body CreateUserRequest
-		body.Name = userName
-
-		stringBody, err := json.Marshal(body)
-
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		finalBody = string(stringBody)
-	}
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//RenameUser names the user associated with the userID to the string userName
-func (client *Client) RenameUser(userID string, userName string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	var body CreateUserRequest
-	body.Name = userName
-
-	stringBody, err := json.Marshal(body)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	finalBody := string(stringBody)
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/name", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//EnableUser enables the user with the given ID
-func (client *Client) EnableUser(userID string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/enable", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//DisableUser enables the user with the given ID
-func (client *Client) DisableUser(userID string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/disable", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END USER FUNCTIONS
-	START USER API KEY FUNCTIONS
-
-*/
-
-//GetAPIKeys gets the public keys associated with this user
-func (client *Client) GetAPIKeys(userID string) GetPublicAPIKeys {
-	r := new(GetPublicAPIKeys)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/keys", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//CreateAPIKeys creates a new public and private key for this user
-func (client *Client) CreateAPIKeys(userID string) CreateAPIKeyReturn {
-	r := new(CreateAPIKeyReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/keys", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//DeleteAPIKeys deletes a public key
-func (client *Client) DeleteAPIKeys(userID string, publicKey string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(DELETE, params, "/v1/users/"+userID+"/keys/"+publicKey, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetAPIKeyPermissions returns the shrimpy permissions for this key
-func (client *Client) GetAPIKeyPermissions(userID string, publicKey string) APIKeyPermissions {
-	r := new(APIKeyPermissions)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/keys/"+publicKey+"/permissions", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//SetAPIKeyPermissions sets the shrimpy permissions for this key
-func (client *Client) SetAPIKeyPermissions(userID string, publicKey string, tradePermission bool, accountPermission bool) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	var permissions APIKeyPermissions
-	permissions.Account = accountPermission
-	permissions.Trade = tradePermission
-	stringBody, err := json.Marshal(permissions)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	finalBody := string(stringBody)
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/keys/"+publicKey+"/permissions", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END USER API KEYS FUNCTIONS
-	START ACCOUNTS FUNCTIONS
-
-*/
-
-//ListAccounts will return an array of exchange accounts linked with this useraccount
-func (client *Client) ListAccounts(userID string) LinkedAccounts {
-	r := new(LinkedAccounts)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetAccount will return a singular linked exchange object
-func (client *Client) GetAccount(userID string, exchangeAccountID string) LinkedExchangeAccount {
-	r := new(LinkedExchangeAccount)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeAccountID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//LinkExchangeAccount will link your exchange keys with this user account
-func (client *Client) LinkExchangeAccount(userID string, exchangeName string, publicKey string, privateKey string, passphrase string) LinkAccountResponse {
-	r := new(LinkAccountResponse)
-	params := ""
-
-	var body LinkAccountRequest
-	body.Exchange = exchangeName
-	body.PrivateKey = privateKey
-	body.PublicKey = publicKey
-
-	if passphrase != "" {
-		body.Passphrase = passphrase
-	}
-
-	stringBody, err := json.Marshal(body)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	finalBody := string(stringBody)
-	fmt.Println(finalBody)
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/accounts", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//UnLinkExchangeAccount will unlike this exchange from your user account
-func (client *Client) UnLinkExchangeAccount(userID string, exchangeID string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(DELETE, params, "/v1/users/"+userID+"/accounts/"+exchangeID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetWhitelistedIPs will unlike this exchange from your user account
-func (client *Client) GetWhitelistedIPs(userID string) WhitelistedIPs {
-	r := new(WhitelistedIPs)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/whitelist", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END ACCOUNTS FUNCTIONS
-	START TRADING FUNCTIONS
-
-*/
-
-//CreateTrade will post a trade for this user to this exchange
-func (client *Client) CreateTrade(userID string, exchangeID string, fromSymbol string, toSymbol string, amount string, smartRouting bool, maxSpreadPercent string, maxSlippagePercent string) CreateTradeResponse {
-	r := new(CreateTradeResponse)
-	params := ""
-
-	var body CreateTradeRequest
-	body.Amount = amount
-	body.FromSymbol = fromSymbol
-	body.ToSymbol = toSymbol
-
-	if smartRouting {
-		body.SmartRouting = smartRouting
-	}
-
-	if maxSpreadPercent != "" {
-		body.MaxSpreadPercent = maxSpreadPercent
-	}
-
-	if maxSlippagePercent != "" {
-		body.MaxSlippagePercent = maxSlippagePercent
-	}
-
-	stringBody, err := json.Marshal(body)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	finalBody := string(stringBody)
-	//fmt.Println(finalBody)
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/trades", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetTradeStatus will return the details of a particular trade
-func (client *Client) GetTradeStatus(userID string, exchangeID string, tradeID string) TradeStatus {
-	r := new(TradeStatus)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/trades/"+tradeID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetActiveTrades will return all trades who status is not 'completed'
-func (client *Client) GetActiveTrades(userID string, exchangeID string) ActiveTrades {
-	r := new(ActiveTrades)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/trades", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END TRADING FUNCTIONS
-	START BALANCE FUNCTIONS
-
-*/
-
-//GetBalance will return the balances on all held assets on that exchange
-func (client *Client) GetBalance(userID string, exchangeID string) ExchangeBalances {
-	//fmt.Println("User ID: " + userID + " Exchange ID: " + exchangeID)
-	r := new(ExchangeBalances)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/balance", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetTotalBalanceHistory gets an aggregate balance history for an exchange account
-func (client *Client) GetTotalBalanceHistory(userID string, exchangeID string) TotalBalanceHistory {
-	r := new(TotalBalanceHistory)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/total_balance_history", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END BALANCE FUNCTIONS
-	START LIMIT ORDER FUNCTIONS
-
-*/
-
-//PlaceLimitOrder posts a limit order to the exchange
-func (client *Client) PlaceLimitOrder(userID string, exchangeID string, baseSymbol string, quoteSymbol string, quantity string, side string, timeInForce string, price string) LimitOrderReturn {
-	r := new(LimitOrderReturn)
-	params := ""
-
-	var body LimitOrderRequest
-	body.BaseSymbol = baseSymbol
-	body.Quantity = quantity
-	body.Price = price
-	body.QuoteSymbol = quoteSymbol
-	body.TimeInForce = timeInForce
-	body.Side = side
-
-	stringBody, err := json.Marshal(body)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	finalBody := string(stringBody)
-	//fmt.Println(finalBody)
-
-	jsonStringReturn := httpDo(POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders", finalBody, client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//GetLimitOrderStatus gets the status of a particular order
-func (client *Client) GetLimitOrderStatus(userID string, exchangeID string, orderID string) LimitOrderStatusReturn {
-	r := new(LimitOrderStatusReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders/"+orderID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//ListOpenOrders gets all orders not in a 'complete' state for a particular exchange
-func (client *Client) ListOpenOrders(userID string, exchangeID string) OpenActiveOrders {
-	r := new(OpenActiveOrders)
-	params := ""
-
-	jsonStringReturn := httpDo(GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders", "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-//CancelLimitOrder cancels as particular orderID from that user/exchange
-func (client *Client) CancelLimitOrder(userID string, exchangeID string, orderID string) SuccessReturn {
-	r := new(SuccessReturn)
-	params := ""
-
-	jsonStringReturn := httpDo(DELETE, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders/"+orderID, "", client.Config.MasterAPIKey, client.Config.MasterSecretKey)
-
-	if client.Config.DebugMessages {
-		fmt.Println(jsonStringReturn)
-	}
-
-	json.Unmarshal([]byte(jsonStringReturn), r)
-	return *r
-}
-
-/*
-
-	END LIMIT ORDER FUNCTIONS
-
-*/
-
-//NewClient initiates a new client object
-func NewClient(config Config) *Client {
-	nonce = time.Now().UnixNano()
-	var client Client
-	client.Config = config
-	return &client
-}
-
-//The function that does all the requesting of resources
-func httpDo(method string, param string, requestPath string, requestBody string, APIKey string, secret string) string {
-	//create a new http client
-	client := &http.Client{}
-
-	//get a new nonce for this request
-	nonce := getNonce()
-
-	//create string builder to concatenate the prehash string
-	var sb strings.Builder
-	sb.WriteString(requestPath)
-	sb.WriteString(method)
-	sb.WriteString(strconv.FormatInt(nonce, 10))
-
-	if requestBody == "{}" {
-		requestBody = ""
-	}
-
-	sb.WriteString(requestBody)
-
-	//Decode secret string
-	decodedSecret, _ := b64.StdEncoding.DecodeString(secret)
-
-	//Create a hew hmac with 256 bit encryption and encode the prehash into it
-	mac := hmac.New(sha256.New, []byte(decodedSecret))
-	mac.Write([]byte(sb.String()))
-	//Encode the resulting hash into the signature
-	apiSigEncode := b64.StdEncoding.EncodeToString(mac.Sum(nil))
-
-	//Form url
-	url := "https://dev-api.shrimpy.io" + requestPath + param
-
-	req, err := http.NewRequest(method, url, strings.NewReader(requestBody))
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	//Set request headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("DEV-SHRIMPY-API-KEY", APIKey)
-	req.Header.Set("DEV-SHRIMPY-API-NONCE", strconv.FormatInt(nonce, 10))
-	req.Header.Set("DEV-SHRIMPY-API-SIGNATURE", apiSigEncode)
-
-	//Send request
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println(err)
-	}
-	defer resp.Body.Close()
-
-	//Read the body out
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	//Return the body as a string
-	return string(body)
-}
-
-//Increments nonce every time it is called
-func getNonce() int64 {
-	nonce = nonce + 1
-	return nonce
-}
-
-//Converts float64's to strings
-func floatToString(nFloat float64) string {
-	return strconv.FormatFloat(nFloat, 'f', -1, 64)
-}
+package shrimpygo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//GET so I don't have to type quotes all the time
+	GET = "GET"
+	//POST so I don't have to type quotes all the time
+	POST = "POST"
+	//DELETE so I don't have to type quotes all the time
+	DELETE = "DELETE"
+)
+
+/*
+
+	START PUBLIC FUNCTIONS
+
+*/
+
+//GetSupportedExchanges returns a list of exchange objects for all exchanges that shrimpy supports
+func (client *Client) GetSupportedExchanges(ctx context.Context) (SupportedExchanges, error) {
+	r := new(SupportedExchanges)
+	err := client.request(ctx, GET, "", "/v1/list_exchanges", "", r)
+	return *r, err
+}
+
+//GetExchangeAssets returns a list of exchange assets for a particular exchange
+func (client *Client) GetExchangeAssets(ctx context.Context, exchangeName string) (Assets, error) {
+	r := new(Assets)
+	err := client.request(ctx, GET, "", "/v1/exchanges/"+exchangeName+"/assets", "", r)
+	return *r, err
+}
+
+//GetExchangePairs returns a list of exchange pairs for a particular exchange
+func (client *Client) GetExchangePairs(ctx context.Context, exchangeName string) (Pairs, error) {
+	r := new(Pairs)
+	err := client.request(ctx, GET, "", "/v1/exchanges/"+exchangeName+"/trading_pairs", "", r)
+	return *r, err
+}
+
+/*
+
+	END PUBLIC FUNCTIONS
+	START MARKET FUNCTIONS
+
+*/
+
+//GetExchangeTickers returns a list of exchange tickers for all exchanges that shrimpy supports
+func (client *Client) GetExchangeTickers(ctx context.Context, exchangeName string) (Tickers, error) {
+	r := new(Tickers)
+	err := client.request(ctx, GET, "", "/v1/exchanges/"+exchangeName+"/ticker", "", r)
+	return *r, err
+}
+
+//GetCandleStickData returns all candlesticks for this exchange
+func (client *Client) GetCandleStickData(ctx context.Context, exchangeName string, quoteTradingSymbol string, baseTradingSymbol string, interval Interval) (CandleSticks, error) {
+	r := new(CandleSticks)
+
+	if err := interval.Validate(); err != nil {
+		return *r, err
+	}
+
+	params := "?quoteTradingSymbol=" + quoteTradingSymbol + "&baseTradingSymbol=" + baseTradingSymbol + "&interval=" + string(interval)
+
+	err := client.request(ctx, GET, params, "/v1/exchanges/"+exchangeName+"/candles", "", r)
+	return *r, err
+}
+
+//GetOrderBooks returns orderbooks for all exchanges in the slice, basesymbol, quotesymbol and limit are all inputs
+func (client *Client) GetOrderBooks(ctx context.Context, sliceExchanges []string, limit, quoteSymbol, baseSymbol string) (ExchangeOrders, error) {
+
+	r := new(ExchangeOrders)
+
+	exchange := "all"
+	sLimit := "&limit=10"
+	qSymbol := ""
+	bSymbol := ""
+
+	if len(sliceExchanges) > 0 {
+		exchange = strings.Join(sliceExchanges, ",")
+	}
+
+	if limit != "" {
+		sLimit = "&limit=" + limit
+	}
+
+	if quoteSymbol != "" {
+		qSymbol = "&quoteSymbol=" + quoteSymbol
+	}
+
+	if baseSymbol != "" {
+		bSymbol = "&baseSymbol=" + baseSymbol
+	}
+
+	params := "?exchange=" + exchange + sLimit + qSymbol + bSymbol
+
+	err := client.request(ctx, GET, params, "/v1/orderbooks", "", r)
+	return *r, err
+}
+
+/*
+
+	END MARKET FUNCTIONS
+	START USER FUNCTIONS - NOTE: You must have the user permission enabled on your Master API key to use these endpoints
+
+*/
+
+//GetUserList returns all users associated with your masterAPI key
+func (client *Client) GetUserList(ctx context.Context) (UsersList, error) {
+	r := new(UsersList)
+
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users", "", r)
+	return *r, err
+}
+
+//GetSingleUserList returns a single user based on ID
+func (client *Client) GetSingleUserList(ctx context.Context, userID string) (SingleUser, error) {
+	r := new(SingleUser)
+
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID, "", r)
+	return *r, err
+}
+
+//CreateUser associates a new user with your account. Returns the ID of that user
+func (client *Client) CreateUser(ctx context.Context, userName string) (UserID, error) {
+	r := new(UserID)
+	params := ""
+	finalBody := ""
+
+	if userName != "" {
+		var body CreateUserRequest
+		body.Name = userName
+
+		stringBody, err := json.Marshal(body)
+
+		if err != nil {
+			return *r, err
+		}
+
+		finalBody = string(stringBody)
+	}
+
+	err := client.request(ctx, POST, params, "/v1/users", finalBody, r)
+	return *r, err
+}
+
+//RenameUser names the user associated with the userID to the string userName
+func (client *Client) RenameUser(ctx context.Context, userID string, userName string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	var body CreateUserRequest
+	body.Name = userName
+
+	stringBody, err := json.Marshal(body)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/name", finalBody, r)
+	return *r, err
+}
+
+//EnableUser enables the user with the given ID
+func (client *Client) EnableUser(ctx context.Context, userID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, POST, params, "/v1/users/"+userID+"/enable", "", r)
+	return *r, err
+}
+
+//DisableUser enables the user with the given ID
+func (client *Client) DisableUser(ctx context.Context, userID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, POST, params, "/v1/users/"+userID+"/disable", "", r)
+	return *r, err
+}
+
+/*
+
+	END USER FUNCTIONS
+	START USER API KEY FUNCTIONS
+
+*/
+
+//GetAPIKeys gets the public keys associated with this user
+func (client *Client) GetAPIKeys(ctx context.Context, userID string) (GetPublicAPIKeys, error) {
+	r := new(GetPublicAPIKeys)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/keys", "", r)
+	return *r, err
+}
+
+//CreateAPIKeys creates a new public and private key for this user
+func (client *Client) CreateAPIKeys(ctx context.Context, userID string) (CreateAPIKeyReturn, error) {
+	r := new(CreateAPIKeyReturn)
+	params := ""
+
+	err := client.request(ctx, POST, params, "/v1/users/"+userID+"/keys", "", r)
+	return *r, err
+}
+
+//DeleteAPIKeys deletes a public key
+func (client *Client) DeleteAPIKeys(ctx context.Context, userID string, publicKey string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, DELETE, params, "/v1/users/"+userID+"/keys/"+publicKey, "", r)
+	return *r, err
+}
+
+//GetAPIKeyPermissions returns the shrimpy permissions for this key
+func (client *Client) GetAPIKeyPermissions(ctx context.Context, userID string, publicKey string) (APIKeyPermissions, error) {
+	r := new(APIKeyPermissions)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/keys/"+publicKey+"/permissions", "", r)
+	return *r, err
+}
+
+//SetAPIKeyPermissions sets the shrimpy permissions for this key
+func (client *Client) SetAPIKeyPermissions(ctx context.Context, userID string, publicKey string, tradePermission bool, accountPermission bool) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	var permissions APIKeyPermissions
+	permissions.Account = accountPermission
+	permissions.Trade = tradePermission
+	stringBody, err := json.Marshal(permissions)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/keys/"+publicKey+"/permissions", finalBody, r)
+	return *r, err
+}
+
+/*
+
+	END USER API KEYS FUNCTIONS
+	START ACCOUNTS FUNCTIONS
+
+*/
+
+//ListAccounts will return an array of exchange accounts linked with this useraccount
+func (client *Client) ListAccounts(ctx context.Context, userID string) (LinkedAccounts, error) {
+	r := new(LinkedAccounts)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts", "", r)
+	return *r, err
+}
+
+//GetAccount will return a singular linked exchange object
+func (client *Client) GetAccount(ctx context.Context, userID string, exchangeAccountID string) (LinkedExchangeAccount, error) {
+	r := new(LinkedExchangeAccount)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeAccountID, "", r)
+	return *r, err
+}
+
+//LinkExchangeAccount will link your exchange keys with this user account
+func (client *Client) LinkExchangeAccount(ctx context.Context, userID string, exchangeName string, publicKey string, privateKey string, passphrase string) (LinkAccountResponse, error) {
+	r := new(LinkAccountResponse)
+	params := ""
+
+	var body LinkAccountRequest
+	body.Exchange = exchangeName
+	body.PrivateKey = privateKey
+	body.PublicKey = publicKey
+
+	if passphrase != "" {
+		body.Passphrase = passphrase
+	}
+
+	stringBody, err := json.Marshal(body)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts", finalBody, r)
+	return *r, err
+}
+
+//UnLinkExchangeAccount will unlike this exchange from your user account
+func (client *Client) UnLinkExchangeAccount(ctx context.Context, userID string, exchangeID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, DELETE, params, "/v1/users/"+userID+"/accounts/"+exchangeID, "", r)
+	return *r, err
+}
+
+//GetWhitelistedIPs will unlike this exchange from your user account
+func (client *Client) GetWhitelistedIPs(ctx context.Context, userID string) (WhitelistedIPs, error) {
+	r := new(WhitelistedIPs)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/whitelist", "", r)
+	return *r, err
+}
+
+/*
+
+	END ACCOUNTS FUNCTIONS
+	START TRADING FUNCTIONS
+
+*/
+
+//CreateTrade will post a trade for this user to this exchange
+func (client *Client) CreateTrade(ctx context.Context, userID string, exchangeID string, fromSymbol string, toSymbol string, amount Decimal, smartRouting bool, maxSpreadPercent Decimal, maxSlippagePercent Decimal) (CreateTradeResponse, error) {
+	return client.SubmitTrade(ctx, userID, exchangeID, newCreateTradeRequest(fromSymbol, toSymbol, amount, smartRouting, maxSpreadPercent, maxSlippagePercent))
+}
+
+//SubmitTrade validates the trade and posts it for this user to this exchange
+func (client *Client) SubmitTrade(ctx context.Context, userID string, exchangeID string, trade CreateTradeRequest) (CreateTradeResponse, error) {
+	return client.submitTrade(ctx, "/v1/users/"+userID+"/accounts/"+exchangeID, trade)
+}
+
+//submitTrade posts a trade to the exchange account at accountPath, shared with UserClient
+func (client *Client) submitTrade(ctx context.Context, accountPath string, trade CreateTradeRequest) (CreateTradeResponse, error) {
+	r := new(CreateTradeResponse)
+	params := ""
+
+	if err := trade.Validate(); err != nil {
+		return *r, err
+	}
+
+	stringBody, err := json.Marshal(trade)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, accountPath+"/trades", finalBody, r)
+	return *r, err
+}
+
+//newCreateTradeRequest builds the body for the positional CreateTrade, zero percentages are left out
+func newCreateTradeRequest(fromSymbol string, toSymbol string, amount Decimal, smartRouting bool, maxSpreadPercent Decimal, maxSlippagePercent Decimal) CreateTradeRequest {
+	trade := CreateTradeRequest{
+		FromSymbol:   fromSymbol,
+		ToSymbol:     toSymbol,
+		Amount:       amount,
+		SmartRouting: smartRouting,
+	}
+
+	if !maxSpreadPercent.IsZero() {
+		trade.MaxSpreadPercent = &maxSpreadPercent
+	}
+
+	if !maxSlippagePercent.IsZero() {
+		trade.MaxSlippagePercent = &maxSlippagePercent
+	}
+
+	return trade
+}
+
+//GetTradeStatus will return the details of a particular trade
+func (client *Client) GetTradeStatus(ctx context.Context, userID string, exchangeID string, tradeID string) (TradeStatus, error) {
+	r := new(TradeStatus)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/trades/"+tradeID, "", r)
+	return *r, err
+}
+
+//GetActiveTrades will return all trades who status is not 'completed'
+func (client *Client) GetActiveTrades(ctx context.Context, userID string, exchangeID string) (ActiveTrades, error) {
+	r := new(ActiveTrades)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/trades", "", r)
+	return *r, err
+}
+
+/*
+
+	END TRADING FUNCTIONS
+	START BALANCE FUNCTIONS
+
+*/
+
+//GetBalance will return the balances on all held assets on that exchange
+func (client *Client) GetBalance(ctx context.Context, userID string, exchangeID string) (ExchangeBalances, error) {
+	r := new(ExchangeBalances)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/balance", "", r)
+	return *r, err
+}
+
+//GetTotalBalanceHistory gets an aggregate balance history for an exchange account
+func (client *Client) GetTotalBalanceHistory(ctx context.Context, userID string, exchangeID string) (TotalBalanceHistory, error) {
+	r := new(TotalBalanceHistory)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/total_balance_history", "", r)
+	return *r, err
+}
+
+/*
+
+	END BALANCE FUNCTIONS
+	START LIMIT ORDER FUNCTIONS
+
+*/
+
+//PlaceLimitOrder posts a limit order to the exchange
+func (client *Client) PlaceLimitOrder(ctx context.Context, userID string, exchangeID string, baseSymbol string, quoteSymbol string, quantity Decimal, side Side, timeInForce TimeInForce, price Decimal) (LimitOrderReturn, error) {
+	order := LimitOrderRequest{
+		BaseSymbol:  baseSymbol,
+		QuoteSymbol: quoteSymbol,
+		Quantity:    quantity,
+		Price:       price,
+		Side:        side,
+		TimeInForce: timeInForce,
+	}
+
+	return client.SubmitLimitOrder(ctx, userID, exchangeID, order)
+}
+
+//SubmitLimitOrder validates the order and posts it to the exchange
+func (client *Client) SubmitLimitOrder(ctx context.Context, userID string, exchangeID string, order LimitOrderRequest) (LimitOrderReturn, error) {
+	return client.submitLimitOrder(ctx, "/v1/users/"+userID+"/accounts/"+exchangeID, order)
+}
+
+//submitLimitOrder posts a limit order to the exchange account at accountPath, shared with UserClient
+func (client *Client) submitLimitOrder(ctx context.Context, accountPath string, order LimitOrderRequest) (LimitOrderReturn, error) {
+	r := new(LimitOrderReturn)
+	params := ""
+
+	if err := order.Validate(); err != nil {
+		return *r, err
+	}
+
+	stringBody, err := json.Marshal(order)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, accountPath+"/orders", finalBody, r)
+	return *r, err
+}
+
+//GetLimitOrderStatus gets the status of a particular order
+func (client *Client) GetLimitOrderStatus(ctx context.Context, userID string, exchangeID string, orderID string) (LimitOrderStatusReturn, error) {
+	r := new(LimitOrderStatusReturn)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders/"+orderID, "", r)
+	return *r, err
+}
+
+//ListOpenOrders gets all orders not in a 'complete' state for a particular exchange
+func (client *Client) ListOpenOrders(ctx context.Context, userID string, exchangeID string) (OpenActiveOrders, error) {
+	r := new(OpenActiveOrders)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders", "", r)
+	return *r, err
+}
+
+//CancelLimitOrder cancels as particular orderID from that user/exchange
+func (client *Client) CancelLimitOrder(ctx context.Context, userID string, exchangeID string, orderID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, DELETE, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/orders/"+orderID, "", r)
+	return *r, err
+}
+
+/*
+
+	END LIMIT ORDER FUNCTIONS
+	START ASSET MANAGEMENT FUNCTIONS
+
+*/
+
+//RebalanceNow starts a rebalance of the exchange account towards its current strategy
+func (client *Client) RebalanceNow(ctx context.Context, userID string, exchangeID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/rebalance", "", r)
+	return *r, err
+}
+
+//GetRebalancePeriod returns how often in hours the exchange account is automatically rebalanced
+func (client *Client) GetRebalancePeriod(ctx context.Context, userID string, exchangeID string) (RebalancePeriod, error) {
+	r := new(RebalancePeriod)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/rebalance_period", "", r)
+	return *r, err
+}
+
+//SetRebalancePeriod sets how often in hours the exchange account is automatically rebalanced, 0 turns automatic rebalancing off
+func (client *Client) SetRebalancePeriod(ctx context.Context, userID string, exchangeID string, rebalancePeriodHours int) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	var body RebalancePeriod
+	body.RebalancePeriod = rebalancePeriodHours
+
+	stringBody, err := json.Marshal(body)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/rebalance_period", finalBody, r)
+	return *r, err
+}
+
+//GetStrategy returns the portfolio strategy for the exchange account
+func (client *Client) GetStrategy(ctx context.Context, userID string, exchangeID string) (Strategy, error) {
+	r := new(Strategy)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/strategy", "", r)
+	return *r, err
+}
+
+//SetStrategy sets the portfolio strategy for the exchange account, it is applied on the next rebalance
+func (client *Client) SetStrategy(ctx context.Context, userID string, exchangeID string, strategy Strategy) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	stringBody, err := json.Marshal(strategy)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/strategy", finalBody, r)
+	return *r, err
+}
+
+//ClearStrategy removes the portfolio strategy from the exchange account so it is no longer rebalanced
+func (client *Client) ClearStrategy(ctx context.Context, userID string, exchangeID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/clear_strategy", "", r)
+	return *r, err
+}
+
+//Allocate sets the portfolio strategy for the exchange account and immediately trades into it
+func (client *Client) Allocate(ctx context.Context, userID string, exchangeID string, strategy Strategy) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	stringBody, err := json.Marshal(strategy)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/users/"+userID+"/accounts/"+exchangeID+"/allocate", finalBody, r)
+	return *r, err
+}
+
+/*
+
+	END ASSET MANAGEMENT FUNCTIONS
+	START WEBSOCKET FUNCTIONS
+
+*/
+
+//GetWebsocketToken returns a token for opening a connection to the websocket feed
+func (client *Client) GetWebsocketToken(ctx context.Context) (WebsocketToken, error) {
+	r := new(WebsocketToken)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/ws/token", "", r)
+	return *r, err
+}
+
+/*
+
+	END WEBSOCKET FUNCTIONS
+	START HISTORICAL FUNCTIONS
+
+*/
+
+//GetHistoricalInstruments returns the instruments historical data is held for, empty filters match everything
+func (client *Client) GetHistoricalInstruments(ctx context.Context, exchange string, baseTradingSymbol string, quoteTradingSymbol string) (HistoricalInstruments, error) {
+	r := new(HistoricalInstruments)
+
+	query := url.Values{}
+	setQuery(query, "exchange", exchange)
+	setQuery(query, "baseTradingSymbol", baseTradingSymbol)
+	setQuery(query, "quoteTradingSymbol", quoteTradingSymbol)
+	params := encodeQuery(query)
+
+	err := client.request(ctx, GET, params, "/v1/historical/instruments", "", r)
+	return *r, err
+}
+
+//GetHistoricalCandles returns candles between startTime and endTime
+func (client *Client) GetHistoricalCandles(ctx context.Context, exchange string, baseTradingSymbol string, quoteTradingSymbol string, startTime time.Time, endTime time.Time, limit int, interval Interval) (CandleSticks, error) {
+	r := new(CandleSticks)
+
+	if err := interval.Validate(); err != nil {
+		return *r, err
+	}
+
+	query := historicalQuery(exchange, baseTradingSymbol, quoteTradingSymbol, startTime, endTime)
+	setQuery(query, "limit", limitString(limit))
+	setQuery(query, "interval", string(interval))
+	params := encodeQuery(query)
+
+	err := client.request(ctx, GET, params, "/v1/historical/candles", "", r)
+	return *r, err
+}
+
+//GetHistoricalTrades returns trades between startTime and endTime, at most limit of them
+func (client *Client) GetHistoricalTrades(ctx context.Context, exchange string, baseTradingSymbol string, quoteTradingSymbol string, startTime time.Time, endTime time.Time, limit int) (HistoricalTrades, error) {
+	r := new(HistoricalTrades)
+
+	query := historicalQuery(exchange, baseTradingSymbol, quoteTradingSymbol, startTime, endTime)
+	setQuery(query, "limit", limitString(limit))
+	params := encodeQuery(query)
+
+	err := client.request(ctx, GET, params, "/v1/historical/trades", "", r)
+	return *r, err
+}
+
+//GetHistoricalOrderBooks returns order book snapshots between startTime and endTime, at most limit of them
+func (client *Client) GetHistoricalOrderBooks(ctx context.Context, exchange string, baseTradingSymbol string, quoteTradingSymbol string, startTime time.Time, endTime time.Time, limit int) (HistoricalOrderBooks, error) {
+	r := new(HistoricalOrderBooks)
+
+	query := historicalQuery(exchange, baseTradingSymbol, quoteTradingSymbol, startTime, endTime)
+	setQuery(query, "limit", limitString(limit))
+	params := encodeQuery(query)
+
+	err := client.request(ctx, GET, params, "/v1/historical/orderbooks", "", r)
+	return *r, err
+}
+
+//GetHistoricalCount returns how many data points exist between startTime and endTime, dataType is "trade" or "orderbook"
+func (client *Client) GetHistoricalCount(ctx context.Context, dataType string, exchange string, baseTradingSymbol string, quoteTradingSymbol string, startTime time.Time, endTime time.Time) (HistoricalCount, error) {
+	r := new(HistoricalCount)
+
+	query := historicalQuery(exchange, baseTradingSymbol, quoteTradingSymbol, startTime, endTime)
+	setQuery(query, "type", dataType)
+	params := encodeQuery(query)
+
+	err := client.request(ctx, GET, params, "/v1/historical/count", "", r)
+	return *r, err
+}
+
+/*
+
+	END HISTORICAL FUNCTIONS
+	START ANALYTICS FUNCTIONS
+
+*/
+
+//GetBacktestAssets returns the assets that can be backtested on the exchange
+func (client *Client) GetBacktestAssets(ctx context.Context, exchange string) (BacktestAssets, error) {
+	r := new(BacktestAssets)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/analytics/backtest/"+exchange+"/assets", "", r)
+	return *r, err
+}
+
+//RunBacktest simulates rebalancing the allocations over the requested period
+func (client *Client) RunBacktest(ctx context.Context, backtest BacktestRequest) (BacktestResult, error) {
+	r := new(BacktestResult)
+	params := ""
+
+	stringBody, err := json.Marshal(backtest)
+
+	if err != nil {
+		return *r, err
+	}
+
+	finalBody := string(stringBody)
+
+	err = client.request(ctx, POST, params, "/v1/analytics/backtest/run", finalBody, r)
+	return *r, err
+}
+
+/*
+
+	END ANALYTICS FUNCTIONS
+	START INSIGHTS FUNCTIONS
+
+*/
+
+//GetAssetDominance returns each asset's share of the total market capitalisation
+func (client *Client) GetAssetDominance(ctx context.Context) (AssetDominance, error) {
+	r := new(AssetDominance)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/insights/asset_dominance", "", r)
+	return *r, err
+}
+
+//GetAssetPopularity returns the assets most held across shrimpy portfolios
+func (client *Client) GetAssetPopularity(ctx context.Context) (AssetPopularity, error) {
+	r := new(AssetPopularity)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/insights/asset_popularity", "", r)
+	return *r, err
+}
+
+/*
+
+	END INSIGHTS FUNCTIONS
+	START MANAGEMENT FUNCTIONS
+
+*/
+
+//GetStatus returns whether the shrimpy api is up
+func (client *Client) GetStatus(ctx context.Context) (APIStatus, error) {
+	r := new(APIStatus)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/management/status", "", r)
+	return *r, err
+}
+
+//GetCredits returns the credits remaining on the master api key
+func (client *Client) GetCredits(ctx context.Context) (Credits, error) {
+	r := new(Credits)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/management/credits", "", r)
+	return *r, err
+}
+
+//GetUsage returns the credits shrimpy has charged the master api key, day by day
+func (client *Client) GetUsage(ctx context.Context) (Usage, error) {
+	r := new(Usage)
+	params := ""
+
+	err := client.request(ctx, GET, params, "/v1/management/usage", "", r)
+	return *r, err
+}
+
+/*
+
+	END MANAGEMENT FUNCTIONS
+
+*/
+
+//NewClient initiates a new client object
+func NewClient(config Config) *Client {
+	var client Client
+	client.Config = config
+	client.nonce = config.NonceSource
+	client.httpClient = newHTTPClient(config)
+	client.limiter = newRateLimiter(config)
+	return &client
+}
+
+//request sends the request and decodes the json response into r
+func (client *Client) request(ctx context.Context, method string, param string, requestPath string, requestBody string, r interface{}) error {
+	ctx, span := client.startCall(ctx, method, param, requestPath)
+
+	err := client.decode(ctx, span, method, param, requestPath, requestBody, r)
+	if span != nil {
+		span.End(err)
+	}
+	return err
+}
+
+//decode sends the request and decodes the json response into r, request wraps it in the call's span
+func (client *Client) decode(ctx context.Context, span CallSpan, method string, param string, requestPath string, requestBody string, r interface{}) error {
+	body, err := client.doWithRetry(ctx, span, method, param, requestPath, requestBody)
+	if err != nil {
+		client.logFailure(ctx, method, requestPath, err)
+		return err
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, r); err != nil {
+		return &DecodeError{Method: method, Path: requestPath, Body: string(body), Err: err}
+	}
+
+	return nil
+}
+
+//The function that does all the requesting of resources.
+//span is the call's span, nil when tracing is off. It is passed in rather than read from ctx, which hooks may have changed.
+func (client *Client) httpDo(ctx context.Context, span CallSpan, attempt int, method string, param string, requestPath string, requestBody string) (_ []byte, err error) {
+	category := categorize(requestPath)
+	info := RequestInfo{Method: method, Path: requestPath, Query: strings.TrimPrefix(param, "?"), Category: category, Attempt: attempt}
+	ctx = client.beforeRequest(ctx, info)
+
+	start := time.Now()
+	statusCode, size := 0, 0
+	defer func() {
+		response := ResponseInfo{RequestInfo: info, StatusCode: statusCode, Latency: time.Since(start), Bytes: size, Err: err}
+		if span != nil {
+			span.Try(response)
+		}
+		client.afterResponse(ctx, response)
+	}()
+
+	endpoint, err := client.Config.endpoint()
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err}
+	}
+
+	//wait for room under the rate limit before taking a nonce, so nonces go out in order
+	if err := client.limiter.wait(ctx, category); err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err}
+	}
+
+	//get a new nonce for this request
+	nonce, err := client.nextNonce()
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err}
+	}
+
+	//create string builder to concatenate the prehash string
+	var sb strings.Builder
+	sb.WriteString(requestPath)
+	sb.WriteString(method)
+	sb.WriteString(strconv.FormatInt(nonce, 10))
+
+	if requestBody == "{}" {
+		requestBody = ""
+	}
+
+	sb.WriteString(requestBody)
+
+	//Decode secret string
+	decodedSecret, err := b64.StdEncoding.DecodeString(client.Config.MasterSecretKey)
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: fmt.Errorf("decoding secret key: %w", err)}
+	}
+
+	//Create a hew hmac with 256 bit encryption and encode the prehash into it
+	mac := hmac.New(sha256.New, []byte(decodedSecret))
+	mac.Write([]byte(sb.String()))
+	//Encode the resulting hash into the signature
+	apiSigEncode := b64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	//Form url
+	url := endpoint + requestPath + param
+
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(requestBody))
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err}
+	}
+
+	//Set request headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DEV-SHRIMPY-API-KEY", client.Config.MasterAPIKey)
+	req.Header.Set("DEV-SHRIMPY-API-NONCE", strconv.FormatInt(nonce, 10))
+	req.Header.Set("DEV-SHRIMPY-API-SIGNATURE", apiSigEncode)
+	if span != nil {
+		span.Inject(req.Header)
+	}
+
+	//Send request
+	httpClient := client.httpClient
+	if httpClient == nil {
+		httpClient = newHTTPClient(client.Config)
+	}
+
+	client.logRequest(ctx, req, requestPath, category, requestBody)
+	sent := time.Now()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err, transport: true}
+	}
+	defer resp.Body.Close()
+
+	statusCode = resp.StatusCode
+	client.limiter.observe(category, resp)
+
+	//Read the body out
+	body, err := ioutil.ReadAll(resp.Body)
+	size = len(body)
+	if err != nil {
+		return nil, &RequestError{Method: method, Path: requestPath, Err: err, transport: true}
+	}
+
+	client.logResponse(ctx, method, requestPath, resp.StatusCode, body, time.Since(sent))
+
+	//Anything outside of 2xx is an error from shrimpy
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(method, requestPath, resp, body)
+	}
+
+	userID := pathUserID(requestPath)
+	if userID == "" {
+		userID = client.userID
+	}
+	client.Config.UsageTracker.Record(userID, category)
+
+	return body, nil
+}
+
+//historicalQuery builds the query shared by the historical endpoints, zero times are left out
+func historicalQuery(exchange string, baseTradingSymbol string, quoteTradingSymbol string, startTime time.Time, endTime time.Time) url.Values {
+	query := url.Values{}
+	setQuery(query, "exchange", exchange)
+	setQuery(query, "baseTradingSymbol", baseTradingSymbol)
+	setQuery(query, "quoteTradingSymbol", quoteTradingSymbol)
+	if !startTime.IsZero() {
+		query.Set("startTime", formatTime(startTime))
+	}
+	if !endTime.IsZero() {
+		query.Set("endTime", formatTime(endTime))
+	}
+	return query
+}
+
+//setQuery sets key only when value is not empty
+func setQuery(query url.Values, key string, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+//encodeQuery turns query into the params string passed to httpDo
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+//limitString leaves the limit out when it is not set
+func limitString(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.Itoa(limit)
+}
+
+//formatTime formats t the way shrimpy expects, ISO 8601 in UTC with milliseconds
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+//Converts float64's to strings
+func floatToString(nFloat float64) string {
+	return strconv.FormatFloat(nFloat, 'f', -1, 64)
+}
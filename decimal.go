@@ -0,0 +1,305 @@
+package shrimpygo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//maxDecimalScale bounds the exponent and the resulting scale ParseDecimal accepts, so a number such as
+//1e2147483647 in a response is rejected instead of building a billion digit integer
+const maxDecimalScale = 10000
+
+//Decimal is an exact base 10 number used for every price, quantity and value shrimpy sends or receives.
+//The zero value is 0. Decimals are immutable, every operation returns a new one.
+//In json a Decimal is written as a string and read from either a string or a number.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+//NewDecimal returns unscaled divided by 10 to the power of scale, NewDecimal(12345, 2) is 123.45
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(unscaled), scale: scale}
+}
+
+//ParseDecimal reads a decimal such as "123.45", "-0.001" or "1e-8"
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa := s
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("shrimpygo: invalid decimal %q", s)
+		}
+		if e > maxDecimalScale || e < -maxDecimalScale {
+			return Decimal{}, fmt.Errorf("shrimpygo: decimal %q is out of range", s)
+		}
+		mantissa, exp = s[:i], e
+	}
+
+	negative := false
+	switch {
+	case strings.HasPrefix(mantissa, "-"):
+		negative, mantissa = true, mantissa[1:]
+	case strings.HasPrefix(mantissa, "+"):
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("shrimpygo: invalid decimal %q", s)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("shrimpygo: decimal %q is out of range", s)
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if negative {
+		coef.Neg(coef)
+	}
+
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+//MustParseDecimal is ParseDecimal for constants, it panics when s is not a decimal
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+//String formats the decimal keeping the decimal places it was created with, so "1.50" stays "1.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+//Float64 returns the nearest float64, for charts and logging rather than arithmetic
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+//Rat returns the decimal as a big.Rat
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+}
+
+//Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+//IsZero reports whether the decimal is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+//Cmp returns -1, 0 or 1 as d is less than, equal to or greater than other
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := align(d, other)
+	return a.Cmp(b)
+}
+
+//Equal reports whether d and other are the same number, "1.5" equals "1.50"
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+//Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{coef: a.Add(a, b), scale: maxScale(d, other)}
+}
+
+//Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	a, b := align(d, other)
+	return Decimal{coef: a.Sub(a, b), scale: maxScale(d, other)}
+}
+
+//Mul returns d * other
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), other.int()), scale: d.scale + other.scale}
+}
+
+//Quo returns d / other rounded half away from zero to at most places decimal places. It panics when other is zero.
+func (d Decimal) Quo(other Decimal, places int32) Decimal {
+	if other.IsZero() {
+		panic("shrimpygo: decimal division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	//d/other = (d.coef * 10^other.scale) / (other.coef * 10^d.scale), scaled up by 10^places
+	num := new(big.Int).Mul(d.int(), pow10(other.scale+places))
+	den := new(big.Int).Mul(other.int(), pow10(d.scale))
+
+	return Decimal{coef: roundQuo(num, den), scale: places}.trim()
+}
+
+//Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+//Abs returns the absolute value of d
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+//Round rounds half away from zero to places decimal places
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{coef: roundQuo(d.int(), pow10(d.scale-places)), scale: places}
+}
+
+//Truncate drops everything past places decimal places, rounding towards zero
+func (d Decimal) Truncate(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{coef: new(big.Int).Quo(d.int(), pow10(d.scale-places)), scale: places}
+}
+
+//MarshalJSON writes the decimal as a json string so no precision is lost on the way
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+//UnmarshalJSON accepts a json string or number. null leaves the decimal unchanged and an empty string reads as 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*d = Decimal{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+//MarshalText writes the decimal for text encodings such as url queries
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+//UnmarshalText reads a decimal written by MarshalText
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+//int returns the unscaled value, the zero value's nil coef reads as 0
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+//trim drops trailing zeros after the decimal point
+func (d Decimal) trim() Decimal {
+	coef, scale := new(big.Int).Set(d.int()), d.scale
+	ten, rem := big.NewInt(10), new(big.Int)
+	for scale > 0 {
+		q, r := new(big.Int).QuoRem(coef, ten, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		coef, scale = q, scale-1
+	}
+	return Decimal{coef: coef, scale: scale}
+}
+
+//align returns fresh copies of both unscaled values brought to the larger scale
+func align(a Decimal, b Decimal) (*big.Int, *big.Int) {
+	scale := maxScale(a, b)
+	x := new(big.Int).Mul(a.int(), pow10(scale-a.scale))
+	y := new(big.Int).Mul(b.int(), pow10(scale-b.scale))
+	return x, y
+}
+
+func maxScale(a Decimal, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+//roundQuo divides num by den rounding half away from zero
+func roundQuo(num *big.Int, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	//compare twice the remainder with the divisor to decide which way to round
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	if twice.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if (num.Sign() < 0) != (den.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,243 @@
+package shrimpygo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"123.45", "123.45"},
+		{"1.50", "1.50"},
+		{"-0.001", "-0.001"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"1e-8", "0.00000001"},
+		{"1.5E3", "1500"},
+		{"-2.5e-2", "-0.025"},
+		{"00012.3400", "12.3400"},
+		{"1e10000", "1" + zeros(10000)},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDecimalInvalid(t *testing.T) {
+	for _, in := range []string{"", "-", ".", "abc", "1.2.3", "1e", "1ex", "--1", "1,5", "0x10"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestParseDecimalOutOfRange(t *testing.T) {
+	for _, in := range []string{"1e10001", "1e-10001", "1e40000000", "1e2147483647", "1e-2147483648", "0." + zeros(10001) + "1"} {
+		start := time.Now()
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%.20q) succeeded, want an out of range error", in)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("ParseDecimal(%.20q) took %s", in, elapsed)
+		}
+	}
+}
+
+func TestDecimalZeroValue(t *testing.T) {
+	var d Decimal
+	if !d.IsZero() || d.String() != "0" || d.Sign() != 0 {
+		t.Errorf("zero value = %s, sign %d", d, d.Sign())
+	}
+	if got := d.Add(MustParseDecimal("1.5")).String(); got != "1.5" {
+		t.Errorf("0 + 1.5 = %s", got)
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		unscaled int64
+		scale    int32
+		want     string
+	}{
+		{12345, 2, "123.45"},
+		{-5, 3, "-0.005"},
+		{7, 0, "7"},
+		{7, -2, "700"},
+	}
+
+	for _, tt := range tests {
+		if got := NewDecimal(tt.unscaled, tt.scale).String(); got != tt.want {
+			t.Errorf("NewDecimal(%d, %d) = %s, want %s", tt.unscaled, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		a, b          string
+		add, sub, mul string
+		cmp           int
+	}{
+		{"1.5", "0.25", "1.75", "1.25", "0.375", 1},
+		{"0.1", "0.2", "0.3", "-0.1", "0.02", -1},
+		{"-3", "1.50", "-1.50", "-4.50", "-4.50", -1},
+		{"2.0", "2", "4.0", "0.0", "4.0", 0},
+	}
+
+	for _, tt := range tests {
+		a, b := MustParseDecimal(tt.a), MustParseDecimal(tt.b)
+		if got := a.Add(b).String(); got != tt.add {
+			t.Errorf("%s + %s = %s, want %s", tt.a, tt.b, got, tt.add)
+		}
+		if got := a.Sub(b).String(); got != tt.sub {
+			t.Errorf("%s - %s = %s, want %s", tt.a, tt.b, got, tt.sub)
+		}
+		if got := a.Mul(b).String(); got != tt.mul {
+			t.Errorf("%s * %s = %s, want %s", tt.a, tt.b, got, tt.mul)
+		}
+		if got := a.Cmp(b); got != tt.cmp {
+			t.Errorf("%s cmp %s = %d, want %d", tt.a, tt.b, got, tt.cmp)
+		}
+	}
+
+	if !MustParseDecimal("1.5").Equal(MustParseDecimal("1.50")) {
+		t.Error("1.5 should equal 1.50")
+	}
+	if got := MustParseDecimal("-2.5").Neg().String(); got != "2.5" {
+		t.Errorf("Neg(-2.5) = %s", got)
+	}
+	if got := MustParseDecimal("-2.5").Abs().String(); got != "2.5" {
+		t.Errorf("Abs(-2.5) = %s", got)
+	}
+}
+
+func TestDecimalQuo(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		places int32
+		want   string
+	}{
+		{"1", "3", 4, "0.3333"},
+		{"2", "3", 4, "0.6667"},
+		{"-2", "3", 4, "-0.6667"},
+		{"10", "4", 8, "2.5"},
+		{"1", "8", 2, "0.13"},
+		{"-1", "8", 2, "-0.13"},
+		{"1.5", "0.5", 18, "3"},
+		{"100", "7", 0, "14"},
+		{"1", "3", -1, "0"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.a).Quo(MustParseDecimal(tt.b), tt.places).String()
+		if got != tt.want {
+			t.Errorf("%s / %s to %d places = %s, want %s", tt.a, tt.b, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalQuoByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Quo by zero did not panic")
+		}
+	}()
+	MustParseDecimal("1").Quo(Decimal{}, 2)
+}
+
+func TestDecimalRoundTruncate(t *testing.T) {
+	tests := []struct {
+		in       string
+		places   int32
+		round    string
+		truncate string
+	}{
+		{"1.2345", 2, "1.23", "1.23"},
+		{"1.235", 2, "1.24", "1.23"},
+		{"-1.235", 2, "-1.24", "-1.23"},
+		{"0.5", 0, "1", "0"},
+		{"-0.5", 0, "-1", "0"},
+		{"1.5", 3, "1.5", "1.5"},
+		{"9.999", 2, "10.00", "9.99"},
+	}
+
+	for _, tt := range tests {
+		d := MustParseDecimal(tt.in)
+		if got := d.Round(tt.places).String(); got != tt.round {
+			t.Errorf("Round(%s, %d) = %s, want %s", tt.in, tt.places, got, tt.round)
+		}
+		if got := d.Truncate(tt.places).String(); got != tt.truncate {
+			t.Errorf("Truncate(%s, %d) = %s, want %s", tt.in, tt.places, got, tt.truncate)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+		D Decimal `json:"d"`
+	}
+	v.D = MustParseDecimal("9")
+
+	if err := json.Unmarshal([]byte(`{"a":"0.10","b":1.5e-3,"c":"","d":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A.String() != "0.10" || v.B.String() != "0.0015" || !v.C.IsZero() || v.D.String() != "9" {
+		t.Errorf("decoded %s %s %s %s", v.A, v.B, v.C, v.D)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":"0.10","b":"0.0015","c":"0","d":"9"}`; string(raw) != want {
+		t.Errorf("encoded %s, want %s", raw, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1e999999}`), &v); err == nil {
+		t.Error("decoding 1e999999 succeeded, want an out of range error")
+	}
+	if err := json.Unmarshal([]byte(`{"a":"abc"}`), &v); err == nil {
+		t.Error(`decoding "abc" succeeded, want an error`)
+	}
+}
+
+func TestDecimalText(t *testing.T) {
+	d := MustParseDecimal("-12.340")
+	text, err := d.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back Decimal
+	if err := back.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if back.String() != "-12.340" {
+		t.Errorf("text round trip = %s", back)
+	}
+}
+
+func zeros(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '0'
+	}
+	return string(b)
+}
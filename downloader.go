@@ -0,0 +1,399 @@
+package shrimpygo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//HistoricalDownloader backfills historical trades or candles for one instrument over a long time range.
+//The range is split into windows that are fetched concurrently, within the client's rate limits, and
+//handed to the caller in time order. Progress is written to CheckpointPath after every window so a
+//killed backfill resumes from the last finished window instead of starting over.
+type HistoricalDownloader struct {
+	Exchange           string
+	BaseTradingSymbol  string
+	QuoteTradingSymbol string
+	//Window is the time range fetched by each request, 1 hour when unset
+	Window time.Duration
+	//Limit is the page size asked for on each request, 1000 when unset
+	Limit int
+	//Concurrency is how many windows are fetched at once, 4 when unset
+	Concurrency int
+	//CheckpointPath is where progress is saved, nothing is saved when empty
+	CheckpointPath string
+
+	client *Client
+}
+
+//downloadCheckpoint is the progress file written after every finished window
+type downloadCheckpoint struct {
+	Kind               string    `json:"kind"`
+	Exchange           string    `json:"exchange"`
+	BaseTradingSymbol  string    `json:"baseTradingSymbol"`
+	QuoteTradingSymbol string    `json:"quoteTradingSymbol"`
+	Interval           string    `json:"interval,omitempty"`
+	StartTime          time.Time `json:"startTime"`
+	EndTime            time.Time `json:"endTime"`
+	CompletedThrough   time.Time `json:"completedThrough"`
+}
+
+//downloadWindow is the half open range [start, end)
+type downloadWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+//NewHistoricalDownloader returns a downloader for one instrument that fetches through this client
+func (client *Client) NewHistoricalDownloader(exchange string, baseTradingSymbol string, quoteTradingSymbol string) *HistoricalDownloader {
+	return &HistoricalDownloader{
+		Exchange:           exchange,
+		BaseTradingSymbol:  baseTradingSymbol,
+		QuoteTradingSymbol: quoteTradingSymbol,
+		client:             client,
+	}
+}
+
+//DownloadTrades fetches every trade in [startTime, endTime) and passes them to handle one window at a time, oldest first.
+//Trades repeated across page boundaries are dropped. If handle returns an error the download stops and the window is not checkpointed.
+//Shrimpy pages by time at millisecond resolution, so when more than Limit trades share one millisecond the ones that do not
+//fit on the first page are skipped. Raise Limit if that matters for a busy instrument.
+func (downloader *HistoricalDownloader) DownloadTrades(ctx context.Context, startTime time.Time, endTime time.Time, handle func(HistoricalTrades) error) error {
+	fetch := func(ctx context.Context, w downloadWindow) (interface{}, error) {
+		return downloader.fetchTrades(ctx, w)
+	}
+	deliver := func(page interface{}) error {
+		return handle(page.(HistoricalTrades))
+	}
+
+	return downloader.run(ctx, "trades", "", startTime, endTime, fetch, deliver)
+}
+
+//DownloadCandles fetches every candle in [startTime, endTime) and passes them to handle one window at a time, oldest first.
+//Candles repeated across page boundaries are dropped. If handle returns an error the download stops and the window is not checkpointed.
+func (downloader *HistoricalDownloader) DownloadCandles(ctx context.Context, startTime time.Time, endTime time.Time, interval Interval, handle func(CandleSticks) error) error {
+	fetch := func(ctx context.Context, w downloadWindow) (interface{}, error) {
+		return downloader.fetchCandles(ctx, w, interval)
+	}
+	deliver := func(page interface{}) error {
+		return handle(page.(CandleSticks))
+	}
+
+	if err := interval.Validate(); err != nil {
+		return err
+	}
+
+	return downloader.run(ctx, "candles", string(interval), startTime, endTime, fetch, deliver)
+}
+
+func (downloader *HistoricalDownloader) window() time.Duration {
+	if downloader.Window > 0 {
+		return downloader.Window
+	}
+	return time.Hour
+}
+
+func (downloader *HistoricalDownloader) limit() int {
+	if downloader.Limit > 0 {
+		return downloader.Limit
+	}
+	return 1000
+}
+
+func (downloader *HistoricalDownloader) concurrency() int {
+	if downloader.Concurrency > 0 {
+		return downloader.Concurrency
+	}
+	return 4
+}
+
+//fetchTrades pages through one window, keeping only trades inside it and dropping repeats at page boundaries.
+//Identical trades in the same millisecond are real and kept, only the ones the previous page already returned are dropped.
+func (downloader *HistoricalDownloader) fetchTrades(ctx context.Context, w downloadWindow) (HistoricalTrades, error) {
+	var out HistoricalTrades
+	var seen pageBoundary
+	cursor := w.start
+	limit := downloader.limit()
+
+	for {
+		page, err := downloader.client.GetHistoricalTrades(ctx, downloader.Exchange, downloader.BaseTradingSymbol, downloader.QuoteTradingSymbol, cursor, w.end, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := pageBoundary{at: cursor}
+		for _, trade := range page {
+			if trade.Time.Before(w.start) || !trade.Time.Before(w.end) {
+				continue
+			}
+
+			key := trade.Price.String() + "|" + trade.Size.String() + "|" + trade.TakerSide
+			if seen.repeat(trade.Time, key) {
+				continue
+			}
+
+			out = append(out, trade)
+			kept.add(trade.Time, key)
+		}
+
+		if len(page) < limit {
+			return out, nil
+		}
+		cursor, seen = nextCursor(cursor, kept)
+		if !cursor.Before(w.end) {
+			return out, nil
+		}
+	}
+}
+
+//fetchCandles pages through one window, keeping only candles inside it and dropping repeats at page boundaries
+func (downloader *HistoricalDownloader) fetchCandles(ctx context.Context, w downloadWindow, interval Interval) (CandleSticks, error) {
+	var out CandleSticks
+	var seen pageBoundary
+	cursor := w.start
+	limit := downloader.limit()
+
+	for {
+		page, err := downloader.client.GetHistoricalCandles(ctx, downloader.Exchange, downloader.BaseTradingSymbol, downloader.QuoteTradingSymbol, cursor, w.end, limit, interval)
+		if err != nil {
+			return nil, err
+		}
+
+		kept := pageBoundary{at: cursor}
+		for _, candle := range page {
+			if candle.Time.Before(w.start) || !candle.Time.Before(w.end) {
+				continue
+			}
+
+			//there is one candle per time, so the time alone identifies it
+			if seen.repeat(candle.Time, "") {
+				continue
+			}
+
+			out = append(out, candle)
+			kept.add(candle.Time, "")
+		}
+
+		if len(page) < limit {
+			return out, nil
+		}
+		cursor, seen = nextCursor(cursor, kept)
+		if !cursor.Before(w.end) {
+			return out, nil
+		}
+	}
+}
+
+//pageBoundary counts the items kept at the newest timestamp of a page. The next page starts at that timestamp,
+//so those items, and only those, come back again.
+type pageBoundary struct {
+	at     time.Time
+	counts map[string]int
+}
+
+//add counts an item that was kept, starting over when it is newer than the ones counted so far
+func (boundary *pageBoundary) add(at time.Time, key string) {
+	if at.Before(boundary.at) {
+		return
+	}
+	if at.After(boundary.at) || boundary.counts == nil {
+		boundary.at = at
+		boundary.counts = make(map[string]int)
+	}
+	boundary.counts[key]++
+}
+
+//repeat reports whether the item was already kept from the previous page, using up one count when it was
+func (boundary *pageBoundary) repeat(at time.Time, key string) bool {
+	if !at.Equal(boundary.at) || boundary.counts[key] == 0 {
+		return false
+	}
+	boundary.counts[key]--
+	return true
+}
+
+//nextCursor starts the next page at the newest item kept, which the next page will repeat. When a whole page
+//shared the cursor's timestamp the cursor is nudged forward a millisecond, shrimpy's resolution, so paging
+//cannot loop forever. The rest of that millisecond is skipped and nothing is left to repeat.
+func nextCursor(cursor time.Time, kept pageBoundary) (time.Time, pageBoundary) {
+	if kept.at.After(cursor) {
+		return kept.at, kept
+	}
+	return cursor.Add(time.Millisecond), pageBoundary{}
+}
+
+//run fetches windows concurrently and delivers them in order, checkpointing after each one
+func (downloader *HistoricalDownloader) run(ctx context.Context, kind string, interval string, startTime time.Time, endTime time.Time, fetch func(context.Context, downloadWindow) (interface{}, error), deliver func(interface{}) error) error {
+	checkpoint := downloadCheckpoint{
+		Kind:               kind,
+		Exchange:           downloader.Exchange,
+		BaseTradingSymbol:  downloader.BaseTradingSymbol,
+		QuoteTradingSymbol: downloader.QuoteTradingSymbol,
+		Interval:           interval,
+		StartTime:          startTime.UTC(),
+		EndTime:            endTime.UTC(),
+		CompletedThrough:   startTime.UTC(),
+	}
+
+	resumeFrom, err := downloader.resume(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	var windows []downloadWindow
+	for start := resumeFrom; start.Before(endTime); start = start.Add(downloader.window()) {
+		end := start.Add(downloader.window())
+		if end.After(endTime) {
+			end = endTime
+		}
+		windows = append(windows, downloadWindow{start: start, end: end})
+	}
+
+	if len(windows) == 0 {
+		return nil
+	}
+
+	//workers are stopped before run returns, so a failed window or handle error stops fetching straight away
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	type result struct {
+		page interface{}
+		err  error
+	}
+
+	//one buffered channel per window lets workers finish out of order while delivery stays in order
+	results := make([]chan result, len(windows))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	//ahead holds a slot for every window handed out but not yet delivered, so fetching runs at most
+	//Concurrency windows ahead of a slow handle instead of buffering the whole range in memory
+	ahead := make(chan struct{}, downloader.concurrency())
+
+	jobs := make(chan int)
+	for n := 0; n < downloader.concurrency(); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				page, err := fetch(ctx, windows[i])
+				results[i] <- result{page: page, err: err}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i := range windows {
+			select {
+			case ahead <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i, w := range windows {
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-ahead
+
+		if r.err != nil {
+			return fmt.Errorf("shrimpygo: downloading %s %s to %s: %w", kind, formatTime(w.start), formatTime(w.end), r.err)
+		}
+
+		if err := deliver(r.page); err != nil {
+			return err
+		}
+
+		checkpoint.CompletedThrough = w.end.UTC()
+		if err := downloader.saveCheckpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//resume returns where to start from, reading the checkpoint when it belongs to the same download
+func (downloader *HistoricalDownloader) resume(want downloadCheckpoint) (time.Time, error) {
+	if downloader.CheckpointPath == "" {
+		return want.StartTime, nil
+	}
+
+	raw, err := os.ReadFile(downloader.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return want.StartTime, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("shrimpygo: reading checkpoint: %w", err)
+	}
+
+	var have downloadCheckpoint
+	if err := json.Unmarshal(raw, &have); err != nil {
+		return time.Time{}, fmt.Errorf("shrimpygo: reading checkpoint: %w", err)
+	}
+
+	if have.Kind != want.Kind || have.Exchange != want.Exchange || have.BaseTradingSymbol != want.BaseTradingSymbol ||
+		have.QuoteTradingSymbol != want.QuoteTradingSymbol || have.Interval != want.Interval ||
+		!have.StartTime.Equal(want.StartTime) || !have.EndTime.Equal(want.EndTime) {
+		return time.Time{}, fmt.Errorf("shrimpygo: checkpoint %s belongs to a different download", downloader.CheckpointPath)
+	}
+
+	return have.CompletedThrough, nil
+}
+
+//saveCheckpoint writes to a temporary file and renames it so a crash never leaves a half written checkpoint
+func (downloader *HistoricalDownloader) saveCheckpoint(checkpoint downloadCheckpoint) error {
+	if downloader.CheckpointPath == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(downloader.CheckpointPath), filepath.Base(downloader.CheckpointPath)+".*")
+	if err != nil {
+		return fmt.Errorf("shrimpygo: writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("shrimpygo: writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("shrimpygo: writing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), downloader.CheckpointPath); err != nil {
+		return fmt.Errorf("shrimpygo: writing checkpoint: %w", err)
+	}
+
+	return nil
+}
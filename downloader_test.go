@@ -0,0 +1,249 @@
+package shrimpygo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var downloadStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+//historicalServer serves trades like the historical trades endpoint: those in [startTime, endTime), oldest first,
+//at most limit of them. A page that starts at the previous page's newest trade repeats it, as shrimpy's do.
+//It records the startTime of every request, and delay slows the answer for a window start.
+type historicalServer struct {
+	trades HistoricalTrades
+	delay  func(start time.Time) time.Duration
+
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (server *historicalServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	start, _ := time.Parse(time.RFC3339, query.Get("startTime"))
+	end, _ := time.Parse(time.RFC3339, query.Get("endTime"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	server.mu.Lock()
+	server.starts = append(server.starts, start)
+	server.mu.Unlock()
+
+	if server.delay != nil {
+		time.Sleep(server.delay(start))
+	}
+
+	page := HistoricalTrades{}
+	for _, trade := range server.trades {
+		if !trade.Time.Before(start) && trade.Time.Before(end) && len(page) < limit {
+			page = append(page, trade)
+		}
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+//requested returns the start times asked for so far
+func (server *historicalServer) requested() []time.Time {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]time.Time(nil), server.starts...)
+}
+
+func newTestDownloader(t *testing.T, server *historicalServer) *HistoricalDownloader {
+	t.Helper()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client := NewClient(Config{Endpoint: httpServer.URL, DisableRateLimit: true})
+	return client.NewHistoricalDownloader("binance", "BTC", "USDT")
+}
+
+func testTrade(at time.Duration, price string) HistoricalTrade {
+	return HistoricalTrade{Time: downloadStart.Add(at), Size: MustParseDecimal("1"), Price: MustParseDecimal(price), TakerSide: "buyer"}
+}
+
+func tradeKeys(trades HistoricalTrades) string {
+	keys := make([]string, len(trades))
+	for i, trade := range trades {
+		keys[i] = formatTime(trade.Time) + "@" + trade.Price.String()
+	}
+	return strings.Join(keys, " ")
+}
+
+func TestDownloadTradesResume(t *testing.T) {
+	//each hour has more trades than a page holds, with identical trades sharing a millisecond across page boundaries
+	var trades HistoricalTrades
+	for hour := 0; hour < 6; hour++ {
+		at := time.Duration(hour) * time.Hour
+		trades = append(trades,
+			testTrade(at+time.Minute, "10"),
+			testTrade(at+2*time.Minute, "11"),
+			testTrade(at+3*time.Minute, "12"),
+			testTrade(at+3*time.Minute, "12"),
+			testTrade(at+3*time.Minute, "13"),
+			testTrade(at+4*time.Minute, "14"),
+		)
+	}
+
+	server := &historicalServer{
+		trades: trades,
+		//earlier windows answer slower, so they finish out of order
+		delay: func(start time.Time) time.Duration {
+			return time.Duration(6-start.Sub(downloadStart)/time.Hour) * 2 * time.Millisecond
+		},
+	}
+	downloader := newTestDownloader(t, server)
+	downloader.Limit = 3
+	downloader.Concurrency = 4
+	downloader.CheckpointPath = filepath.Join(t.TempDir(), "trades.json")
+
+	end := downloadStart.Add(6 * time.Hour)
+	errKilled := errors.New("killed")
+
+	var got HistoricalTrades
+	var windows []time.Time
+	handle := func(page HistoricalTrades) error {
+		//the process dies while handling the fourth window
+		if len(windows) == 3 {
+			return errKilled
+		}
+		if len(page) > 0 {
+			windows = append(windows, page[0].Time.Truncate(time.Hour))
+		}
+		got = append(got, page...)
+		return nil
+	}
+
+	if err := downloader.DownloadTrades(context.Background(), downloadStart, end, handle); !errors.Is(err, errKilled) {
+		t.Fatalf("first run = %v, want the handle error", err)
+	}
+	if len(got) != 18 {
+		t.Fatalf("first run delivered %d trades, want the 18 of three windows", len(got))
+	}
+
+	raw, err := os.ReadFile(downloader.CheckpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checkpoint downloadCheckpoint
+	if err := json.Unmarshal(raw, &checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	if want := downloadStart.Add(3 * time.Hour); !checkpoint.CompletedThrough.Equal(want) {
+		t.Errorf("checkpoint completed through %s, want %s", formatTime(checkpoint.CompletedThrough), formatTime(want))
+	}
+
+	//the second run starts from the checkpoint and fetches nothing before it
+	requestedBefore := len(server.requested())
+	resumed := downloadStart.Add(3 * time.Hour)
+	if err := downloader.DownloadTrades(context.Background(), downloadStart, end, func(page HistoricalTrades) error {
+		if len(page) > 0 {
+			windows = append(windows, page[0].Time.Truncate(time.Hour))
+		}
+		got = append(got, page...)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, start := range server.requested()[requestedBefore:] {
+		if start.Before(resumed) {
+			t.Errorf("resumed run fetched from %s, before the checkpoint", formatTime(start))
+		}
+	}
+
+	if got, want := tradeKeys(got), tradeKeys(trades); got != want {
+		t.Errorf("trades =\n%s\nwant\n%s", got, want)
+	}
+	for i, window := range windows {
+		if want := downloadStart.Add(time.Duration(i) * time.Hour); !window.Equal(want) {
+			t.Errorf("window %d delivered starts at %s, want %s", i, formatTime(window), formatTime(want))
+		}
+	}
+}
+
+func TestDownloadTradesNudge(t *testing.T) {
+	//three identical trades in one millisecond fill a page of two, twice, so paging has to move past that millisecond
+	at := time.Second
+	trades := HistoricalTrades{
+		testTrade(at, "10"),
+		testTrade(at, "10"),
+		testTrade(at, "10"),
+		testTrade(at+time.Millisecond, "11"),
+		testTrade(2*time.Second, "12"),
+	}
+
+	server := &historicalServer{trades: trades}
+	downloader := newTestDownloader(t, server)
+	downloader.Limit = 2
+
+	var got HistoricalTrades
+	if err := downloader.DownloadTrades(context.Background(), downloadStart, downloadStart.Add(time.Hour), func(page HistoricalTrades) error {
+		got = append(got, page...)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	//the third trade in the crowded millisecond does not fit on a page and is skipped, as documented
+	want := HistoricalTrades{trades[0], trades[1], trades[3], trades[4]}
+	if got, want := tradeKeys(got), tradeKeys(want); got != want {
+		t.Errorf("trades = %s, want %s", got, want)
+	}
+
+	nudged := downloadStart.Add(at + time.Millisecond)
+	found := false
+	for _, start := range server.requested() {
+		found = found || start.Equal(nudged)
+	}
+	if !found {
+		t.Errorf("no page started at %s, requested %v", formatTime(nudged), server.requested())
+	}
+}
+
+func TestDownloadCheckpointMismatch(t *testing.T) {
+	downloader := newTestDownloader(t, &historicalServer{})
+	downloader.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint.json")
+
+	end := downloadStart.Add(2 * time.Hour)
+	handle := func(HistoricalTrades) error { return nil }
+	if err := downloader.DownloadTrades(context.Background(), downloadStart, end, handle); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		download func() error
+	}{
+		{"different end", func() error {
+			return downloader.DownloadTrades(context.Background(), downloadStart, end.Add(time.Hour), handle)
+		}},
+		{"candles", func() error {
+			return downloader.DownloadCandles(context.Background(), downloadStart, end, Interval1h, func(CandleSticks) error { return nil })
+		}},
+	}
+
+	for _, tt := range tests {
+		err := tt.download()
+		if err == nil || !strings.Contains(err.Error(), "belongs to a different download") {
+			t.Errorf("%s: %v, want a checkpoint mismatch", tt.name, err)
+		}
+	}
+
+	//the same download again is already complete
+	if err := downloader.DownloadTrades(context.Background(), downloadStart, end, func(HistoricalTrades) error {
+		t.Error("a finished download delivered again")
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
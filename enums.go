@@ -0,0 +1,192 @@
+package shrimpygo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Side is the side of a limit order or fill
+type Side string
+
+const (
+	//SideBuy buys the base symbol with the quote symbol
+	SideBuy Side = "BUY"
+	//SideSell sells the base symbol for the quote symbol
+	SideSell Side = "SELL"
+)
+
+//ParseSide reads a side in any case, such as "buy" or "SELL"
+func ParseSide(s string) (Side, error) {
+	side := Side(strings.ToUpper(s))
+	if err := side.Validate(); err != nil {
+		return "", err
+	}
+	return side, nil
+}
+
+//Validate returns an error wrapping ErrInvalidValue unless the side is SideBuy or SideSell
+func (side Side) Validate() error {
+	switch side {
+	case SideBuy, SideSell:
+		return nil
+	}
+	return fmt.Errorf("%w: side %q", ErrInvalidValue, string(side))
+}
+
+//TimeInForce is how long a limit order stays on the book
+type TimeInForce string
+
+const (
+	//TimeInForceGTC keeps the order open until it fills or is cancelled
+	TimeInForceGTC TimeInForce = "GTC"
+	//TimeInForceIOC fills what it can straight away and cancels the rest
+	TimeInForceIOC TimeInForce = "IOC"
+)
+
+//ParseTimeInForce reads a time in force in any case, such as "gtc" or "IOC"
+func ParseTimeInForce(s string) (TimeInForce, error) {
+	timeInForce := TimeInForce(strings.ToUpper(s))
+	if err := timeInForce.Validate(); err != nil {
+		return "", err
+	}
+	return timeInForce, nil
+}
+
+//Validate returns an error wrapping ErrInvalidValue unless the time in force is TimeInForceGTC or TimeInForceIOC
+func (timeInForce TimeInForce) Validate() error {
+	switch timeInForce {
+	case TimeInForceGTC, TimeInForceIOC:
+		return nil
+	}
+	return fmt.Errorf("%w: time in force %q", ErrInvalidValue, string(timeInForce))
+}
+
+//OrderStatus is how far a limit order has got
+type OrderStatus string
+
+const (
+	//OrderStatusQueued is waiting to be sent to the exchange
+	OrderStatusQueued OrderStatus = "queued"
+	//OrderStatusStarted is being sent to the exchange
+	OrderStatusStarted OrderStatus = "started"
+	//OrderStatusOpen is resting on the exchange's book
+	OrderStatusOpen OrderStatus = "open"
+	//OrderStatusClosed has stopped, either filled or cancelled
+	OrderStatusClosed OrderStatus = "closed"
+	//OrderStatusCompleted has finished and its balance changes are final
+	OrderStatusCompleted OrderStatus = "completed"
+)
+
+//ParseOrderStatus reads an order status in any case
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	status := OrderStatus(strings.ToLower(s))
+	if err := status.Validate(); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+//Validate returns an error wrapping ErrInvalidValue for a status shrimpy does not document
+func (status OrderStatus) Validate() error {
+	switch status {
+	case OrderStatusQueued, OrderStatusStarted, OrderStatusOpen, OrderStatusClosed, OrderStatusCompleted:
+		return nil
+	}
+	return fmt.Errorf("%w: order status %q", ErrInvalidValue, string(status))
+}
+
+//Done reports whether the order will not change any more
+func (status OrderStatus) Done() bool {
+	return status == OrderStatusClosed || status == OrderStatusCompleted
+}
+
+//TradeState is how far a trade has got. It is not called TradeStatus because that name is the GetTradeStatus response.
+type TradeState string
+
+const (
+	//TradeStateQueued is waiting to be sent to the exchange
+	TradeStateQueued TradeState = "queued"
+	//TradeStateStarted is being executed on the exchange
+	TradeStateStarted TradeState = "started"
+	//TradeStateCompleted has finished, check Success to see whether it went through
+	TradeStateCompleted TradeState = "completed"
+)
+
+//ParseTradeState reads a trade status in any case
+func ParseTradeState(s string) (TradeState, error) {
+	state := TradeState(strings.ToLower(s))
+	if err := state.Validate(); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+//Validate returns an error wrapping ErrInvalidValue for a status shrimpy does not document
+func (state TradeState) Validate() error {
+	switch state {
+	case TradeStateQueued, TradeStateStarted, TradeStateCompleted:
+		return nil
+	}
+	return fmt.Errorf("%w: trade status %q", ErrInvalidValue, string(state))
+}
+
+//Done reports whether the trade will not change any more
+func (state TradeState) Done() bool {
+	return state == TradeStateCompleted
+}
+
+//Interval is the time covered by one candle
+type Interval string
+
+const (
+	//Interval1m is one minute candles
+	Interval1m Interval = "1m"
+	//Interval5m is five minute candles
+	Interval5m Interval = "5m"
+	//Interval15m is fifteen minute candles
+	Interval15m Interval = "15m"
+	//Interval1h is one hour candles
+	Interval1h Interval = "1h"
+	//Interval6h is six hour candles
+	Interval6h Interval = "6h"
+	//Interval1d is one day candles
+	Interval1d Interval = "1d"
+)
+
+//ParseInterval reads an interval in any case, such as "1h" or "1D"
+func ParseInterval(s string) (Interval, error) {
+	interval := Interval(strings.ToLower(s))
+	if err := interval.Validate(); err != nil {
+		return "", err
+	}
+	return interval, nil
+}
+
+//Validate returns an error wrapping ErrInvalidValue unless the interval is one of the Interval constants
+func (interval Interval) Validate() error {
+	switch interval {
+	case Interval1m, Interval5m, Interval15m, Interval1h, Interval6h, Interval1d:
+		return nil
+	}
+	return fmt.Errorf("%w: interval %q", ErrInvalidValue, string(interval))
+}
+
+//Duration returns the time covered by one candle
+func (interval Interval) Duration() time.Duration {
+	switch interval {
+	case Interval1m:
+		return time.Minute
+	case Interval5m:
+		return 5 * time.Minute
+	case Interval15m:
+		return 15 * time.Minute
+	case Interval1h:
+		return time.Hour
+	case Interval6h:
+		return 6 * time.Hour
+	case Interval1d:
+		return 24 * time.Hour
+	}
+	return 0
+}
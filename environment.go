@@ -0,0 +1,97 @@
+package shrimpygo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	//EnvironmentProduction is shrimpy's hosted developer api, used when no environment is configured
+	EnvironmentProduction = "production"
+	//EnvironmentLocal points at a stand-in server running on this machine
+	EnvironmentLocal = "local"
+)
+
+//ErrUnknownEnvironment is returned when Config.Environment names a profile that has not been registered
+var ErrUnknownEnvironment = errors.New("shrimpygo: unknown environment")
+
+//Environment holds the hosts a client talks to
+type Environment struct {
+	Endpoint          string
+	WebsocketEndpoint string
+}
+
+var (
+	environmentsMu sync.RWMutex
+	environments   = map[string]Environment{
+		EnvironmentProduction: {
+			Endpoint:          "https://dev-api.shrimpy.io",
+			WebsocketEndpoint: "wss://ws-feed.shrimpy.io",
+		},
+		EnvironmentLocal: {
+			Endpoint:          "http://localhost:8080",
+			WebsocketEndpoint: "ws://localhost:8080",
+		},
+	}
+)
+
+//RegisterEnvironment adds or replaces a named environment profile.
+//Only production and local are built in, a sandbox or staging host has to be registered under a name of your own.
+func RegisterEnvironment(name string, env Environment) {
+	environmentsMu.Lock()
+	defer environmentsMu.Unlock()
+	environments[name] = env
+}
+
+//LookupEnvironment returns the environment profile registered under name
+func LookupEnvironment(name string) (Environment, bool) {
+	environmentsMu.RLock()
+	defer environmentsMu.RUnlock()
+	env, ok := environments[name]
+	return env, ok
+}
+
+//environment resolves the profile for this config, defaulting to production
+func (config Config) environment() (Environment, error) {
+	name := config.Environment
+	if name == "" {
+		name = EnvironmentProduction
+	}
+
+	env, ok := LookupEnvironment(name)
+	if !ok {
+		return Environment{}, fmt.Errorf("%w %q", ErrUnknownEnvironment, name)
+	}
+
+	return env, nil
+}
+
+//endpoint returns the rest host for this config. Config.Endpoint always wins over the environment profile
+func (config Config) endpoint() (string, error) {
+	if config.Endpoint != "" {
+		return strings.TrimRight(config.Endpoint, "/"), nil
+	}
+
+	env, err := config.environment()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(env.Endpoint, "/"), nil
+}
+
+//websocketEndpoint returns the websocket host for this config. Config.WebsocketEndpoint always wins over the environment profile
+func (config Config) websocketEndpoint() (string, error) {
+	if config.WebsocketEndpoint != "" {
+		return strings.TrimRight(config.WebsocketEndpoint, "/"), nil
+	}
+
+	env, err := config.environment()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(env.WebsocketEndpoint, "/"), nil
+}
@@ -0,0 +1,37 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{Environment: "test-sandbox", DisableRateLimit: true})
+	if _, err := client.GetSupportedExchanges(context.Background()); !errors.Is(err, ErrUnknownEnvironment) {
+		t.Errorf("unregistered environment = %v, want ErrUnknownEnvironment", err)
+	}
+
+	RegisterEnvironment("test-sandbox", Environment{Endpoint: server.URL + "/", WebsocketEndpoint: "ws://sandbox.test/"})
+	if _, err := client.GetSupportedExchanges(context.Background()); err != nil {
+		t.Errorf("registered environment = %v", err)
+	}
+	if got, err := client.Config.websocketEndpoint(); err != nil || got != "ws://sandbox.test" {
+		t.Errorf("websocket endpoint = %s, %v", got, err)
+	}
+
+	//Endpoint wins over the environment
+	if got, _ := (Config{Environment: "test-sandbox", Endpoint: "http://override.test"}).endpoint(); got != "http://override.test" {
+		t.Errorf("endpoint = %s, want the override", got)
+	}
+	if got, _ := (Config{}).endpoint(); got != "https://dev-api.shrimpy.io" {
+		t.Errorf("default endpoint = %s, want production", got)
+	}
+}
@@ -0,0 +1,308 @@
+package shrimpygo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//APIError is returned when shrimpy answers a request with a non 2xx status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Code       int
+	Message    string
+	Body       string
+	//RetryAfter is how long shrimpy asked us to wait before trying again, zero when not sent
+	RetryAfter time.Duration
+}
+
+//apiErrorBody is the error payload shrimpy sends back on a failed request
+type apiErrorBody struct {
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	ErrorCode int    `json:"errorCode"`
+	Code      int    `json:"code"`
+}
+
+//newAPIError builds an APIError from the status code and body of a failed request
+func newAPIError(method string, path string, resp *http.Response, body []byte) *APIError {
+	statusCode := resp.StatusCode
+	e := &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: statusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	var b apiErrorBody
+	if json.Unmarshal(body, &b) == nil {
+		e.Message = b.Error
+		if e.Message == "" {
+			e.Message = b.Message
+		}
+
+		e.Code = b.ErrorCode
+		if e.Code == 0 {
+			e.Code = b.Code
+		}
+	}
+
+	if e.Message == "" {
+		e.Message = http.StatusText(statusCode)
+	}
+
+	return e
+}
+
+//parseRetryAfter reads a Retry-After header given either in seconds or as a http date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("shrimpygo: %s %s: %d %s (code %d)", e.Method, e.Path, e.StatusCode, e.Message, e.Code)
+	}
+	return fmt.Sprintf("shrimpygo: %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+//RequestError is returned when a request could not be built or sent, or the response could not be read
+type RequestError struct {
+	Method string
+	Path   string
+	Err    error
+
+	//transport is set when the request was sent but the round trip failed
+	transport bool
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("shrimpygo: %s %s: %v", e.Method, e.Path, e.Err)
+}
+
+//Unwrap returns the underlying transport error
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+//DecodeError is returned when a successful response body could not be decoded
+type DecodeError struct {
+	Method string
+	Path   string
+	Body   string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("shrimpygo: %s %s: decoding response: %v", e.Method, e.Path, e.Err)
+}
+
+//Unwrap returns the underlying json error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+//ExchangeErrorKind classifies an error an exchange returned to shrimpy
+type ExchangeErrorKind string
+
+const (
+	//ExchangeErrorUnknown is any error that does not match a known kind
+	ExchangeErrorUnknown ExchangeErrorKind = "unknown"
+	//ExchangeErrorInvalidCredentials means the linked exchange keys are wrong, expired or lack permissions
+	ExchangeErrorInvalidCredentials ExchangeErrorKind = "invalid_credentials"
+	//ExchangeErrorInsufficientFunds means the account could not cover a trade or order
+	ExchangeErrorInsufficientFunds ExchangeErrorKind = "insufficient_funds"
+	//ExchangeErrorIPNotWhitelisted means the exchange keys are locked to ips that do not include shrimpy's
+	ExchangeErrorIPNotWhitelisted ExchangeErrorKind = "ip_not_whitelisted"
+	//ExchangeErrorExchangeDown means the exchange was unavailable, overloaded or under maintenance
+	ExchangeErrorExchangeDown ExchangeErrorKind = "exchange_down"
+)
+
+//Sentinels matched by errors.Is against an ExchangeAPIError of the same kind
+var (
+	ErrInvalidCredentials = errors.New("shrimpygo: exchange rejected credentials")
+	ErrInsufficientFunds  = errors.New("shrimpygo: insufficient funds on exchange")
+	ErrIPNotWhitelisted   = errors.New("shrimpygo: ip not whitelisted on exchange")
+	ErrExchangeDown       = errors.New("shrimpygo: exchange unavailable")
+)
+
+//exchangeErrorPatterns are checked in order against the lower cased message, the first match wins.
+//IP is checked before credentials since exchanges often mention the api key when rejecting an ip.
+//Funds keywords name funds or balance, a bare "insufficient" also shows up in "insufficient permissions".
+var exchangeErrorPatterns = []struct {
+	kind     ExchangeErrorKind
+	keywords []string
+}{
+	{ExchangeErrorIPNotWhitelisted, []string{"whitelist", "ip address", "ip not", "ip restrict", "unauthorized ip"}},
+	{ExchangeErrorInsufficientFunds, []string{
+		"insufficient funds", "insufficient_funds", "insufficientfunds", "insufficient balance", "insufficient_balance",
+		"insufficient account balance", "balance insufficient", "not enough balance", "not enough funds",
+		"not enough exchange balance", "exceeds balance", "balance too low",
+	}},
+	{ExchangeErrorInvalidCredentials, []string{"api key", "api-key", "apikey", "signature", "credential", "unauthori", "authenticat", "permission", "invalid key", "key expired"}},
+	{ExchangeErrorExchangeDown, []string{"maintenance", "unavailable", "timed out", "timeout", "overload", "bad gateway", "system busy", "try again"}},
+}
+
+//ExchangeAPIError is an error an exchange returned to shrimpy while it acted on a linked account
+type ExchangeAPIError struct {
+	Code     int       `json:"code"`
+	Message  string    `json:"message"`
+	Exchange string    `json:"exchange"`
+	Time     time.Time `json:"time"`
+}
+
+//UnmarshalJSON accepts the error as an object, with the code as a number or a string, or as a bare message string.
+//The time may be sent as time or timestamp.
+func (e *ExchangeAPIError) UnmarshalJSON(data []byte) error {
+	var message string
+	if json.Unmarshal(data, &message) == nil {
+		*e = ExchangeAPIError{Message: message}
+		return nil
+	}
+
+	var raw struct {
+		Code      json.RawMessage `json:"code"`
+		ErrorCode json.RawMessage `json:"errorCode"`
+		Message   string          `json:"message"`
+		Error     string          `json:"error"`
+		Exchange  string          `json:"exchange"`
+		Time      json.RawMessage `json:"time"`
+		Timestamp json.RawMessage `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = ExchangeAPIError{Message: raw.Message, Exchange: raw.Exchange}
+	if e.Message == "" {
+		e.Message = raw.Error
+	}
+
+	//a time in an unexpected format is dropped rather than failing the whole response
+	if json.Unmarshal(raw.Time, &e.Time) != nil || e.Time.IsZero() {
+		e.Time = time.Time{}
+		_ = json.Unmarshal(raw.Timestamp, &e.Time)
+	}
+
+	code := raw.Code
+	if len(code) == 0 {
+		code = raw.ErrorCode
+	}
+	if len(code) > 0 {
+		var s string
+		if json.Unmarshal(code, &s) == nil {
+			code = json.RawMessage(s)
+		}
+		e.Code, _ = strconv.Atoi(string(code))
+	}
+
+	return nil
+}
+
+func (e ExchangeAPIError) Error() string {
+	prefix := "shrimpygo: exchange error"
+	if e.Exchange != "" {
+		prefix = "shrimpygo: " + e.Exchange + " error"
+	}
+	if e.Code != 0 {
+		return fmt.Sprintf("%s %d: %s", prefix, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", prefix, e.Message)
+}
+
+//Kind classifies the error from its message
+func (e ExchangeAPIError) Kind() ExchangeErrorKind {
+	message := strings.ToLower(e.Message)
+	for _, pattern := range exchangeErrorPatterns {
+		for _, keyword := range pattern.keywords {
+			if strings.Contains(message, keyword) {
+				return pattern.kind
+			}
+		}
+	}
+	return ExchangeErrorUnknown
+}
+
+//Is lets errors.Is match the error against ErrInvalidCredentials, ErrInsufficientFunds, ErrIPNotWhitelisted or ErrExchangeDown
+func (e ExchangeAPIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidCredentials:
+		return e.Kind() == ExchangeErrorInvalidCredentials
+	case ErrInsufficientFunds:
+		return e.Kind() == ExchangeErrorInsufficientFunds
+	case ErrIPNotWhitelisted:
+		return e.Kind() == ExchangeErrorIPNotWhitelisted
+	case ErrExchangeDown:
+		return e.Kind() == ExchangeErrorExchangeDown
+	}
+	return false
+}
+
+//IsInvalidCredentials reports whether the linked exchange keys were rejected
+func (e ExchangeAPIError) IsInvalidCredentials() bool {
+	return e.Kind() == ExchangeErrorInvalidCredentials
+}
+
+//IsInsufficientFunds reports whether the account could not cover the trade or order
+func (e ExchangeAPIError) IsInsufficientFunds() bool {
+	return e.Kind() == ExchangeErrorInsufficientFunds
+}
+
+//IsIPNotWhitelisted reports whether the exchange keys do not allow shrimpy's ips
+func (e ExchangeAPIError) IsIPNotWhitelisted() bool {
+	return e.Kind() == ExchangeErrorIPNotWhitelisted
+}
+
+//IsExchangeDown reports whether the exchange was unavailable
+func (e ExchangeAPIError) IsExchangeDown() bool {
+	return e.Kind() == ExchangeErrorExchangeDown
+}
+
+//ExchangeAPIErrors is the list of exchange errors on a linked account, trade or order
+type ExchangeAPIErrors []ExchangeAPIError
+
+//Has reports whether any of the errors is of this kind
+func (errs ExchangeAPIErrors) Has(kind ExchangeErrorKind) bool {
+	for _, e := range errs {
+		if e.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+//Latest returns the most recent error, the last one when none carry a time. It is false when the list is empty.
+func (errs ExchangeAPIErrors) Latest() (ExchangeAPIError, bool) {
+	if len(errs) == 0 {
+		return ExchangeAPIError{}, false
+	}
+
+	latest := errs[len(errs)-1]
+	for _, e := range errs {
+		if e.Time.After(latest.Time) {
+			latest = e
+		}
+	}
+	return latest, true
+}
@@ -0,0 +1,113 @@
+package shrimpygo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExchangeErrorKind(t *testing.T) {
+	tests := []struct {
+		message string
+		want    ExchangeErrorKind
+	}{
+		{"Account has insufficient balance for requested action.", ExchangeErrorInsufficientFunds},
+		{"EOrder:Insufficient funds", ExchangeErrorInsufficientFunds},
+		{"INSUFFICIENT_FUNDS", ExchangeErrorInsufficientFunds},
+		{"InsufficientFunds", ExchangeErrorInsufficientFunds},
+		{"Balance insufficient!", ExchangeErrorInsufficientFunds},
+		{"Invalid order: not enough exchange balance for 1.5 BTCUSD at 9000", ExchangeErrorInsufficientFunds},
+		{"Order amount exceeds balance", ExchangeErrorInsufficientFunds},
+
+		{"API-key format invalid.", ExchangeErrorInvalidCredentials},
+		{"Signature for this request is not valid.", ExchangeErrorInvalidCredentials},
+		{"EAPI:Invalid key", ExchangeErrorInvalidCredentials},
+		{"API key has insufficient permissions", ExchangeErrorInvalidCredentials},
+		{"Insufficient privileges for this API key", ExchangeErrorInvalidCredentials},
+		{"Unauthorized", ExchangeErrorInvalidCredentials},
+		{"EGeneral:Permission denied", ExchangeErrorInvalidCredentials},
+		//binance names the ip as one of several causes, without saying it is the one
+		{"Invalid API-key, IP, or permissions for action.", ExchangeErrorInvalidCredentials},
+
+		{"IP address not whitelisted for this API key", ExchangeErrorIPNotWhitelisted},
+		{"Unauthorized IP", ExchangeErrorIPNotWhitelisted},
+
+		{"EService:Unavailable", ExchangeErrorExchangeDown},
+		{"The system is under maintenance, please try again later", ExchangeErrorExchangeDown},
+		{"Timeout waiting for response from backend server. Send status unknown; execution status unknown.", ExchangeErrorExchangeDown},
+		{"502 Bad Gateway", ExchangeErrorExchangeDown},
+		{"System busy", ExchangeErrorExchangeDown},
+
+		{"Filter failure: LOT_SIZE", ExchangeErrorUnknown},
+		{"", ExchangeErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		e := ExchangeAPIError{Message: tt.message}
+		if got := e.Kind(); got != tt.want {
+			t.Errorf("Kind(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestExchangeErrorIs(t *testing.T) {
+	sentinels := map[ExchangeErrorKind]error{
+		ExchangeErrorInvalidCredentials: ErrInvalidCredentials,
+		ExchangeErrorInsufficientFunds:  ErrInsufficientFunds,
+		ExchangeErrorIPNotWhitelisted:   ErrIPNotWhitelisted,
+		ExchangeErrorExchangeDown:       ErrExchangeDown,
+	}
+
+	var err error = ExchangeAPIError{Exchange: "binance", Message: "Account has insufficient balance for requested action."}
+	for kind, sentinel := range sentinels {
+		if got, want := errors.Is(err, sentinel), kind == ExchangeErrorInsufficientFunds; got != want {
+			t.Errorf("errors.Is(%v, %v) = %t, want %t", err, sentinel, got, want)
+		}
+	}
+}
+
+func TestExchangeErrorUnmarshal(t *testing.T) {
+	at := time.Date(2019, 5, 2, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   string
+		want ExchangeAPIError
+	}{
+		{"number code", `{"code":2010,"message":"Account has insufficient balance for requested action.","exchange":"binance","time":"2019-05-02T10:30:00Z"}`,
+			ExchangeAPIError{Code: 2010, Message: "Account has insufficient balance for requested action.", Exchange: "binance", Time: at}},
+		{"string code", `{"code":"-2015","message":"Invalid API-key, IP, or permissions for action.","exchange":"binance"}`,
+			ExchangeAPIError{Code: -2015, Message: "Invalid API-key, IP, or permissions for action.", Exchange: "binance"}},
+		{"errorCode and error", `{"errorCode":"EAPI:Invalid key","error":"EAPI:Invalid key","exchange":"kraken","timestamp":"2019-05-02T10:30:00Z"}`,
+			ExchangeAPIError{Message: "EAPI:Invalid key", Exchange: "kraken", Time: at}},
+		{"bad time", `{"code":1,"message":"System busy","time":"yesterday"}`,
+			ExchangeAPIError{Code: 1, Message: "System busy"}},
+		{"bare message", `"EOrder:Insufficient funds"`,
+			ExchangeAPIError{Message: "EOrder:Insufficient funds"}},
+	}
+
+	for _, tt := range tests {
+		var got ExchangeAPIError
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.Code != tt.want.Code || got.Message != tt.want.Message || got.Exchange != tt.want.Exchange || !got.Time.Equal(tt.want.Time) {
+			t.Errorf("%s = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	//accounts mix both forms in one list
+	var account LinkedExchangeAccount
+	body := `{"id":7,"exchange":"binance","exchangeApiErrors":["Unauthorized",{"code":2010,"message":"Account has insufficient balance for requested action.","time":"2019-05-02T10:30:00Z"}]}`
+	if err := json.Unmarshal([]byte(body), &account); err != nil {
+		t.Fatal(err)
+	}
+	if !account.ExchangeAPIErrors.Has(ExchangeErrorInvalidCredentials) || !account.ExchangeAPIErrors.Has(ExchangeErrorInsufficientFunds) {
+		t.Errorf("errors = %+v, want invalid credentials and insufficient funds", account.ExchangeAPIErrors)
+	}
+	if latest, ok := account.ExchangeAPIErrors.Latest(); !ok || latest.Code != 2010 {
+		t.Errorf("Latest = %+v, %t, want code 2010", latest, ok)
+	}
+}
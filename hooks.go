@@ -0,0 +1,78 @@
+package shrimpygo
+
+import (
+	"context"
+	"time"
+)
+
+//Hooks observe every try of every api call, for metrics, auditing or tracing, without wrapping the Client methods.
+//A retried GET calls the hooks once per try.
+type Hooks interface {
+	//BeforeRequest is called before the try waits for the rate limiter and is sent.
+	//The context it returns is used for the try and handed to AfterResponse, so it can carry state such as a start time.
+	BeforeRequest(ctx context.Context, info RequestInfo) context.Context
+	//AfterResponse is called once the try has finished, whether it succeeded or not
+	AfterResponse(ctx context.Context, info ResponseInfo)
+}
+
+//RequestInfo describes one try of an api call
+type RequestInfo struct {
+	Method string
+	//Path is the request path without the query, such as /v1/users/<userID>/accounts
+	Path     string
+	Query    string
+	Category EndpointCategory
+	//Attempt counts the tries of this call, starting at 1
+	Attempt int
+}
+
+//ResponseInfo describes how one try of an api call ended
+type ResponseInfo struct {
+	RequestInfo
+	//StatusCode is zero when no response was received
+	StatusCode int
+	//Latency is the time since BeforeRequest, including any wait for the rate limiter
+	Latency time.Duration
+	//Bytes is the size of the response body
+	Bytes int
+	//Err is the error the try failed with, an *APIError for a non 2xx status, nil on success
+	Err error
+}
+
+//HookFuncs lets ordinary functions be used as Hooks, either func may be nil
+type HookFuncs struct {
+	BeforeRequestFunc func(ctx context.Context, info RequestInfo) context.Context
+	AfterResponseFunc func(ctx context.Context, info ResponseInfo)
+}
+
+//BeforeRequest calls BeforeRequestFunc when it is set
+func (hooks HookFuncs) BeforeRequest(ctx context.Context, info RequestInfo) context.Context {
+	if hooks.BeforeRequestFunc == nil {
+		return ctx
+	}
+	return hooks.BeforeRequestFunc(ctx, info)
+}
+
+//AfterResponse calls AfterResponseFunc when it is set
+func (hooks HookFuncs) AfterResponse(ctx context.Context, info ResponseInfo) {
+	if hooks.AfterResponseFunc != nil {
+		hooks.AfterResponseFunc(ctx, info)
+	}
+}
+
+//beforeRequest runs every hook in order, each one getting the context returned by the one before
+func (client *Client) beforeRequest(ctx context.Context, info RequestInfo) context.Context {
+	for _, hooks := range client.Config.Hooks {
+		if next := hooks.BeforeRequest(ctx, info); next != nil {
+			ctx = next
+		}
+	}
+	return ctx
+}
+
+//afterResponse runs every hook in order
+func (client *Client) afterResponse(ctx context.Context, info ResponseInfo) {
+	for _, hooks := range client.Config.Hooks {
+		hooks.AfterResponse(ctx, info)
+	}
+}
@@ -0,0 +1,177 @@
+package shrimpygo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//redacted replaces every secret before it reaches a log
+const redacted = "[REDACTED]"
+
+//maxLoggedBody caps how much of a request or response body is logged
+const maxLoggedBody = 4096
+
+//redactedHeaders are the request headers that carry the api key or the signature made with the secret key
+var redactedHeaders = map[string]bool{
+	"Dev-Shrimpy-Api-Key":       true,
+	"Dev-Shrimpy-Api-Signature": true,
+	"Authorization":             true,
+}
+
+//redactedFields are json fields, compared in lower case, whose values are keys, secrets or tokens.
+//They show up in LinkExchangeAccount and CreateAPIKeys bodies and in the websocket token response.
+var redactedFields = map[string]bool{
+	"apikey":     true,
+	"publickey":  true,
+	"privatekey": true,
+	"secret":     true,
+	"secretkey":  true,
+	"passphrase": true,
+	"password":   true,
+	"signature":  true,
+	"token":      true,
+}
+
+//debugLogger is used when DebugMessages is set without a Logger
+var debugLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+//logger returns Config.Logger, a debug logger writing to stderr when only DebugMessages is set, or nil when logging is off
+func (client *Client) logger() *slog.Logger {
+	if client.Config.Logger != nil {
+		return client.Config.Logger
+	}
+	if client.Config.DebugMessages {
+		return debugLogger
+	}
+	return nil
+}
+
+//log writes one record when the logger is on and level is enabled, attrs is only called then so redaction costs nothing otherwise
+func (client *Client) log(ctx context.Context, level slog.Level, msg string, attrs func() []slog.Attr) {
+	logger := client.logger()
+	if logger == nil || !logger.Enabled(ctx, level) {
+		return
+	}
+	logger.LogAttrs(ctx, level, msg, attrs()...)
+}
+
+//logRequest logs a signed request just before it is sent
+func (client *Client) logRequest(ctx context.Context, req *http.Request, requestPath string, category EndpointCategory, requestBody string) {
+	client.log(ctx, slog.LevelDebug, "shrimpy request", func() []slog.Attr {
+		return []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("path", requestPath),
+			slog.String("query", req.URL.RawQuery),
+			slog.String("category", string(category)),
+			redactHeaders(req.Header),
+			slog.String("body", redactBody([]byte(requestBody))),
+		}
+	})
+}
+
+//logResponse logs the response to a request, whatever its status
+func (client *Client) logResponse(ctx context.Context, method string, requestPath string, statusCode int, body []byte, latency time.Duration) {
+	client.log(ctx, slog.LevelDebug, "shrimpy response", func() []slog.Attr {
+		return []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", requestPath),
+			slog.Int("status", statusCode),
+			slog.Duration("latency", latency),
+			slog.Int("bytes", len(body)),
+			slog.String("body", redactBody(body)),
+		}
+	})
+}
+
+//logRetry logs a failed try that is about to be retried
+func (client *Client) logRetry(ctx context.Context, method string, requestPath string, attempt int, wait time.Duration, err error) {
+	client.log(ctx, slog.LevelWarn, "shrimpy retry", func() []slog.Attr {
+		return []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", requestPath),
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			slog.String("error", err.Error()),
+		}
+	})
+}
+
+//logFailure logs a call that returned an error to the caller
+func (client *Client) logFailure(ctx context.Context, method string, requestPath string, err error) {
+	client.log(ctx, slog.LevelError, "shrimpy request failed", func() []slog.Attr {
+		return []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", requestPath),
+			slog.String("error", err.Error()),
+		}
+	})
+}
+
+//redactHeaders groups the request headers in a stable order with the api key and signature replaced
+func redactHeaders(header http.Header) slog.Attr {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+//redactBody returns a json body with the values of redactedFields replaced, at any depth.
+//Bodies that are not json are logged as they are. Either way the result is cut to maxLoggedBody.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if decoder.Decode(&value) == nil {
+		if redactedBody, err := json.Marshal(redactValue(value)); err == nil {
+			body = redactedBody
+		}
+	}
+
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "...(" + strconv.Itoa(len(body)-maxLoggedBody) + " more bytes)"
+	}
+	return string(body)
+}
+
+//redactValue walks a decoded json value replacing the values of redactedFields
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	}
+	return value
+}
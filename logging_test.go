@@ -0,0 +1,128 @@
+package shrimpygo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testLogUserID = "701e0d16-1e9e-42c9-b6a1-4cada1f395b8"
+
+func TestLoggingRedactsSecrets(t *testing.T) {
+	var mu sync.Mutex
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		signatures = append(signatures, r.Header.Get("DEV-SHRIMPY-API-SIGNATURE"))
+		mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/keys"):
+			w.Write([]byte(`{"publicKey":"created-public-key","privateKey":"created-private-key"}`))
+		case r.URL.Path == "/v1/ws/token":
+			w.Write([]byte(`{"token":"websocket-token"}`))
+		default:
+			w.Write([]byte(`{"id":42}`))
+		}
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(Config{
+		Endpoint:         server.URL,
+		MasterAPIKey:     "master-api-key",
+		MasterSecretKey:  "c2VjcmV0",
+		DisableRateLimit: true,
+		Logger:           slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+
+	ctx := context.Background()
+	if _, err := client.LinkExchangeAccount(ctx, testLogUserID, "coinbasepro", "exchange-public-key", "exchange-private-key", "exchange-passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateAPIKeys(ctx, testLogUserID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetWebsocketToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	secrets := []string{
+		"master-api-key", "c2VjcmV0",
+		"exchange-public-key", "exchange-private-key", "exchange-passphrase",
+		"created-public-key", "created-private-key",
+		"websocket-token",
+	}
+	mu.Lock()
+	secrets = append(secrets, signatures...)
+	mu.Unlock()
+
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(out, secret) {
+			t.Errorf("log contains %q:\n%s", secret, out)
+		}
+	}
+
+	//the records are still useful: both directions of every call, with the headers that are not secret
+	if got := strings.Count(out, `"msg":"shrimpy request"`); got != 3 {
+		t.Errorf("%d request records, want 3", got)
+	}
+	if got := strings.Count(out, `"msg":"shrimpy response"`); got != 3 {
+		t.Errorf("%d response records, want 3", got)
+	}
+	for _, want := range []string{"Dev-Shrimpy-Api-Nonce", `"exchange\":\"coinbasepro\"`, redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log is missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestLoggingLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(Config{
+		Endpoint:         server.URL,
+		DisableRateLimit: true,
+		Logger:           slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})),
+	})
+
+	if _, err := client.GetSupportedExchanges(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("a successful call logged above debug:\n%s", buf.String())
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{``, ``},
+		{`{"privateKey":"x","amount":"1.50"}`, `{"amount":"1.50","privateKey":"[REDACTED]"}`},
+		{`[{"nested":{"Token":"x","price":1.50}}]`, `[{"nested":{"Token":"[REDACTED]","price":1.50}}]`},
+		{`not json`, `not json`},
+	}
+
+	for _, tt := range tests {
+		if got := redactBody([]byte(tt.in)); got != tt.want {
+			t.Errorf("redactBody(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	long := redactBody(bytes.Repeat([]byte("a"), maxLoggedBody+10))
+	if !strings.HasSuffix(long, "...(10 more bytes)") {
+		t.Errorf("long body not cut: %s", long[maxLoggedBody:])
+	}
+}
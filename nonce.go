@@ -0,0 +1,111 @@
+package shrimpygo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//NonceSource hands out the nonce signed into every request.
+//Shrimpy rejects a nonce that is not greater than the last one it saw for a key, so
+//implementations must never repeat or go backwards, even when called from many goroutines.
+type NonceSource interface {
+	NextNonce() (int64, error)
+}
+
+//ClockNonceSource issues nonces from the wall clock in nanoseconds, bumping by one when
+//two calls land on the same tick or the clock steps backwards. The zero value is ready to use.
+//Processes sharing a master key stay ordered as long as their clocks are in sync.
+type ClockNonceSource struct {
+	last int64
+}
+
+//NextNonce returns the larger of the current time and the previous nonce plus one
+func (s *ClockNonceSource) NextNonce() (int64, error) {
+	for {
+		last := atomic.LoadInt64(&s.last)
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+
+		if atomic.CompareAndSwapInt64(&s.last, last, next) {
+			return next, nil
+		}
+	}
+}
+
+//FileNonceSource keeps the last nonce in a file so the sequence survives restarts and can be
+//shared by every process on the host using the same master key. The file is locked around each
+//read-increment-write where the platform supports it.
+type FileNonceSource struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+//NewFileNonceSource returns a FileNonceSource persisting to path, the file is created on first use
+func NewFileNonceSource(path string) *FileNonceSource {
+	return &FileNonceSource{Path: path}
+}
+
+//NextNonce reads the stored nonce, advances it past both that value and the wall clock, and writes it back
+func (s *FileNonceSource) NextNonce() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("opening nonce file: %w", err)
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return 0, fmt.Errorf("locking nonce file: %w", err)
+	}
+	defer unlockFile(f)
+
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return 0, fmt.Errorf("reading nonce file: %w", err)
+	}
+
+	var last int64
+	if raw = bytes.TrimSpace(raw); len(raw) > 0 {
+		last, err = strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parsing nonce file: %w", err)
+		}
+	}
+
+	next := time.Now().UnixNano()
+	if next <= last {
+		next = last + 1
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("writing nonce file: %w", err)
+	}
+
+	if _, err := f.WriteAt([]byte(strconv.FormatInt(next, 10)), 0); err != nil {
+		return 0, fmt.Errorf("writing nonce file: %w", err)
+	}
+
+	return next, nil
+}
+
+//nextNonce pulls from the configured source, falling back to a clock source for clients not built by NewClient
+func (client *Client) nextNonce() (int64, error) {
+	client.nonceOnce.Do(func() {
+		if client.nonce == nil {
+			client.nonce = new(ClockNonceSource)
+		}
+	})
+
+	return client.nonce.NextNonce()
+}
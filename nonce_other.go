@@ -0,0 +1,16 @@
+//go:build !unix
+
+package shrimpygo
+
+import (
+	"os"
+)
+
+//lockFile is a no-op where flock is unavailable, FileNonceSource is then only safe within one process
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,455 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//ErrSequenceGap is reported when an order book update does not follow on from the previous one
+var ErrSequenceGap = errors.New("shrimpygo: order book sequence gap")
+
+//ErrBookNotSynced is returned by queries on a book that has not received a snapshot yet
+var ErrBookNotSynced = errors.New("shrimpygo: order book not synced")
+
+//snapshotTimeout is how long a book that missed an update waits for a websocket snapshot before reloading from rest
+const snapshotTimeout = 10 * time.Second
+
+//OrderBookSnapshot is a consistent copy of a local order book, bids best first and asks best first
+type OrderBookSnapshot struct {
+	Exchange  string
+	Pair      string
+	Sequence  int64
+	UpdatedAt time.Time
+	Bids      []OrderBookLevel
+	Asks      []OrderBookLevel
+}
+
+//Depth describes walking one side of the book to fill a quantity
+type Depth struct {
+	//Quantity is how much could be filled, less than asked for when the book is too thin
+	Quantity Decimal
+	//Cost is the quote amount paid or received for Quantity
+	Cost Decimal
+	//AveragePrice is Cost divided by Quantity, to at most 18 decimal places
+	AveragePrice Decimal
+	//WorstPrice is the last price level touched
+	WorstPrice Decimal
+	//Levels is how many price levels were touched
+	Levels int
+	//Complete is false when the book did not hold the full quantity
+	Complete bool
+}
+
+//LocalOrderBook is an order book for one exchange and pair kept up to date from the websocket.
+//All methods are safe to call while updates are being applied.
+type LocalOrderBook struct {
+	Exchange string
+	Pair     string
+
+	mu        sync.RWMutex
+	bids      []OrderBookLevel
+	asks      []OrderBookLevel
+	sequence  int64
+	synced    bool
+	anySeq    bool
+	updatedAt time.Time
+
+	//disconnected wakes the manager's run for the book when the websocket drops, so it can arm its fallback
+	disconnected chan struct{}
+}
+
+//Synced reports whether the book holds a snapshot and has seen every update since.
+//It is false from a websocket disconnect until the snapshot sent after reconnecting.
+func (book *LocalOrderBook) Synced() bool {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return book.synced
+}
+
+//Sequence returns the sequence number of the last applied update
+func (book *LocalOrderBook) Sequence() int64 {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return book.sequence
+}
+
+//BestBid returns the highest bid, false when there are no bids or the book is not synced
+func (book *LocalOrderBook) BestBid() (OrderBookLevel, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	if !book.synced || len(book.bids) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return book.bids[0], true
+}
+
+//BestAsk returns the lowest ask, false when there are no asks or the book is not synced
+func (book *LocalOrderBook) BestAsk() (OrderBookLevel, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	if !book.synced || len(book.asks) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return book.asks[0], true
+}
+
+//Snapshot copies the top depth levels of each side, every level when depth is zero or less
+func (book *LocalOrderBook) Snapshot(depth int) (OrderBookSnapshot, error) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	if !book.synced {
+		return OrderBookSnapshot{}, ErrBookNotSynced
+	}
+	return OrderBookSnapshot{
+		Exchange:  book.Exchange,
+		Pair:      book.Pair,
+		Sequence:  book.sequence,
+		UpdatedAt: book.updatedAt,
+		Bids:      copyLevels(book.bids, depth),
+		Asks:      copyLevels(book.asks, depth),
+	}, nil
+}
+
+//BuyDepth walks the asks to see what buying quantity of the base asset would cost
+func (book *LocalOrderBook) BuyDepth(quantity Decimal) (Depth, error) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	if !book.synced {
+		return Depth{}, ErrBookNotSynced
+	}
+	return walkDepth(book.asks, quantity)
+}
+
+//SellDepth walks the bids to see what selling quantity of the base asset would return
+func (book *LocalOrderBook) SellDepth(quantity Decimal) (Depth, error) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+
+	if !book.synced {
+		return Depth{}, ErrBookNotSynced
+	}
+	return walkDepth(book.bids, quantity)
+}
+
+//apply applies a websocket message, returning ErrSequenceGap when an update was missed
+func (book *LocalOrderBook) apply(msg OrderBookMessage) error {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	if msg.Snapshot {
+		book.bids, book.asks = nil, nil
+		book.synced = true
+		book.anySeq = false
+	} else {
+		if !book.synced {
+			return nil
+		}
+
+		if !book.anySeq && msg.Sequence != book.sequence+1 {
+			book.synced = false
+			return fmt.Errorf("%w: %s %s expected %d got %d", ErrSequenceGap, book.Exchange, book.Pair, book.sequence+1, msg.Sequence)
+		}
+		book.anySeq = false
+	}
+
+	for _, content := range msg.Content {
+		for _, l := range content.Bids {
+			setLevel(&book.bids, l, true)
+		}
+		for _, l := range content.Asks {
+			setLevel(&book.asks, l, false)
+		}
+	}
+
+	book.sequence = msg.Sequence
+	book.updatedAt = time.Now()
+	return nil
+}
+
+//unsync stops the book answering queries until the next snapshot, updates until then are ignored
+func (book *LocalOrderBook) unsync() {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	book.synced = false
+}
+
+//load replaces the book with a rest snapshot. Rest snapshots carry no sequence, so whatever update comes next is accepted.
+func (book *LocalOrderBook) load(bids []OrderBookLevel, asks []OrderBookLevel) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	book.bids, book.asks = nil, nil
+	for _, l := range bids {
+		setLevel(&book.bids, l, true)
+	}
+	for _, l := range asks {
+		setLevel(&book.asks, l, false)
+	}
+
+	book.synced = true
+	book.anySeq = true
+	book.sequence = 0
+	book.updatedAt = time.Now()
+}
+
+//setLevel inserts, replaces or with a zero quantity removes a level keeping the side sorted best first
+func setLevel(side *[]OrderBookLevel, l OrderBookLevel, descending bool) {
+	levels := *side
+	i := sort.Search(len(levels), func(i int) bool {
+		if descending {
+			return levels[i].Price.Cmp(l.Price) <= 0
+		}
+		return levels[i].Price.Cmp(l.Price) >= 0
+	})
+	found := i < len(levels) && levels[i].Price.Cmp(l.Price) == 0
+
+	switch {
+	case l.Quantity.IsZero() && found:
+		*side = append(levels[:i], levels[i+1:]...)
+	case l.Quantity.IsZero():
+	case found:
+		levels[i] = l
+	default:
+		levels = append(levels, OrderBookLevel{})
+		copy(levels[i+1:], levels[i:])
+		levels[i] = l
+		*side = levels
+	}
+}
+
+func copyLevels(levels []OrderBookLevel, depth int) []OrderBookLevel {
+	if depth <= 0 || depth > len(levels) {
+		depth = len(levels)
+	}
+
+	out := make([]OrderBookLevel, depth)
+	copy(out, levels)
+	return out
+}
+
+//walkDepth fills quantity from the best level down
+func walkDepth(levels []OrderBookLevel, quantity Decimal) (Depth, error) {
+	if quantity.Sign() <= 0 {
+		return Depth{}, fmt.Errorf("shrimpygo: bad quantity %s", quantity)
+	}
+
+	var d Depth
+	for _, l := range levels {
+		if d.Quantity.Cmp(quantity) >= 0 {
+			break
+		}
+
+		take := quantity.Sub(d.Quantity)
+		if l.Quantity.Cmp(take) < 0 {
+			take = l.Quantity
+		}
+
+		d.Quantity = d.Quantity.Add(take)
+		d.Cost = d.Cost.Add(take.Mul(l.Price))
+		d.WorstPrice = l.Price
+		d.Levels++
+	}
+
+	d.Complete = d.Quantity.Cmp(quantity) >= 0
+	if d.Quantity.Sign() > 0 {
+		d.AveragePrice = d.Cost.Quo(d.Quantity, 18)
+	}
+
+	return d, nil
+}
+
+//OrderBookManager keeps local order books in sync from the websocket orderbook channel.
+//A book that misses an update resubscribes to get a fresh snapshot, falling back to GetOrderBooks
+//when none arrives, and then carries on from the stream. Books are unsynced while the websocket is down.
+type OrderBookManager struct {
+	client *Client
+	ws     *WebsocketClient
+
+	mu    sync.RWMutex
+	books map[subscriptionKey]*LocalOrderBook
+}
+
+//NewOrderBookManager returns a manager that streams through ws and resyncs through this client
+func (client *Client) NewOrderBookManager(ws *WebsocketClient) *OrderBookManager {
+	manager := &OrderBookManager{
+		client: client,
+		ws:     ws,
+		books:  make(map[subscriptionKey]*LocalOrderBook),
+	}
+	ws.onDisconnect(manager.unsyncAll)
+	return manager
+}
+
+//Watch subscribes to the order book for a pair such as "btc-usdt" and keeps a local copy of it
+func (manager *OrderBookManager) Watch(ctx context.Context, exchange string, pair string) (*LocalOrderBook, error) {
+	key := newSubscriptionKey(ChannelOrderBook, exchange, pair)
+
+	manager.mu.Lock()
+	if book, ok := manager.books[key]; ok {
+		manager.mu.Unlock()
+		return book, nil
+	}
+	book := &LocalOrderBook{Exchange: key.exchange, Pair: key.pair, disconnected: make(chan struct{}, 1)}
+	manager.books[key] = book
+	manager.mu.Unlock()
+
+	ch, err := manager.ws.SubscribeOrderBook(ctx, exchange, pair)
+	if err != nil {
+		manager.mu.Lock()
+		delete(manager.books, key)
+		manager.mu.Unlock()
+		return nil, err
+	}
+
+	go manager.run(book, ch)
+	return book, nil
+}
+
+//Unwatch unsubscribes from the pair and forgets its book
+func (manager *OrderBookManager) Unwatch(ctx context.Context, exchange string, pair string) error {
+	key := newSubscriptionKey(ChannelOrderBook, exchange, pair)
+
+	manager.mu.Lock()
+	delete(manager.books, key)
+	manager.mu.Unlock()
+
+	return manager.ws.Unsubscribe(ctx, ChannelOrderBook, exchange, pair)
+}
+
+//Book returns the local book for a pair that is being watched
+func (manager *OrderBookManager) Book(exchange string, pair string) (*LocalOrderBook, bool) {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	book, ok := manager.books[newSubscriptionKey(ChannelOrderBook, exchange, pair)]
+	return book, ok
+}
+
+//unsyncAll marks every book unsynced when the websocket drops, the snapshots replayed on reconnect sync them again.
+//Each book's run arms its fallback in case its snapshot never comes.
+func (manager *OrderBookManager) unsyncAll() {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+
+	for _, book := range manager.books {
+		book.unsync()
+		select {
+		case book.disconnected <- struct{}{}:
+		default:
+		}
+	}
+}
+
+//run applies messages until the subscription channel is closed.
+//After a sequence gap or a disconnect it waits for a fresh websocket snapshot and only reloads from rest when none arrives in time.
+func (manager *OrderBookManager) run(book *LocalOrderBook, ch <-chan OrderBookMessage) {
+	fallback := time.NewTimer(snapshotTimeout)
+	fallback.Stop()
+	defer fallback.Stop()
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			err := book.apply(msg)
+			if err == nil {
+				continue
+			}
+
+			manager.ws.reportError(err)
+			if errors.Is(err, ErrSequenceGap) {
+				if err := manager.ws.refresh(context.Background(), ChannelOrderBook, book.Exchange, book.Pair); err != nil {
+					manager.ws.reportError(err)
+				}
+				fallback.Reset(snapshotTimeout)
+			}
+
+		case <-book.disconnected:
+			fallback.Reset(snapshotTimeout)
+
+		case <-fallback.C:
+			if book.Synced() {
+				continue
+			}
+			//while the websocket is down check again later, the snapshot replayed on reconnect normally syncs the book first
+			if !manager.ws.connected() {
+				fallback.Reset(snapshotTimeout)
+				continue
+			}
+
+			if err := manager.resync(book); err != nil {
+				manager.ws.reportError(err)
+				continue
+			}
+			if !manager.skipQueued(book, ch) {
+				return
+			}
+		}
+	}
+}
+
+//skipQueued drops the updates that queued up while the rest snapshot was fetched, they are older than it and
+//would overwrite fresher levels. A websocket snapshot among them starts a consistent run, so it and everything
+//after it are applied. It returns false when the channel was closed.
+func (manager *OrderBookManager) skipQueued(book *LocalOrderBook, ch <-chan OrderBookMessage) bool {
+	fresh := false
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return false
+			}
+
+			fresh = fresh || msg.Snapshot
+			if !fresh {
+				continue
+			}
+			if err := book.apply(msg); err != nil {
+				manager.ws.reportError(err)
+			}
+		default:
+			return true
+		}
+	}
+}
+
+//resync reloads a book from the rest order book endpoint
+func (manager *OrderBookManager) resync(book *LocalOrderBook) error {
+	base, quote, ok := strings.Cut(book.Pair, "-")
+	if !ok {
+		return fmt.Errorf("shrimpygo: resync %s: pair %q is not base-quote", book.Exchange, book.Pair)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	orders, err := manager.client.GetOrderBooks(ctx, []string{book.Exchange}, "1000", strings.ToUpper(quote), strings.ToUpper(base))
+	if err != nil {
+		return fmt.Errorf("shrimpygo: resync %s %s: %w", book.Exchange, book.Pair, err)
+	}
+
+	for _, market := range orders {
+		for _, exchangeBook := range market.OrderBooks {
+			if !strings.EqualFold(exchangeBook.Exchange, book.Exchange) {
+				continue
+			}
+
+			book.load(exchangeBook.OrderBook.Bids, exchangeBook.OrderBook.Asks)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("shrimpygo: resync %s %s: no order book returned", book.Exchange, book.Pair)
+}
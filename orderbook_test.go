@@ -0,0 +1,202 @@
+package shrimpygo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//bookMessage decodes an orderbook channel message the way the websocket does
+func bookMessage(t *testing.T, snapshot bool, sequence int64, asks string, bids string) OrderBookMessage {
+	t.Helper()
+
+	raw := fmt.Sprintf(`{"exchange":"binance","pair":"ltc-btc","channel":"orderbook","snapshot":%t,"sequence":%d,"content":{"asks":[%s],"bids":[%s]}}`, snapshot, sequence, asks, bids)
+	var msg OrderBookMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+//levels formats a side as price:quantity pairs, best first
+func levels(side []OrderBookLevel) string {
+	out := make([]string, len(side))
+	for i, l := range side {
+		out[i] = l.Price.String() + ":" + l.Quantity.String()
+	}
+	return strings.Join(out, " ")
+}
+
+func TestLocalOrderBookUpdates(t *testing.T) {
+	book := &LocalOrderBook{Exchange: "binance", Pair: "ltc-btc"}
+
+	messages := []OrderBookMessage{
+		bookMessage(t, true, 7,
+			`{"price":"1.2","quantity":"1"},{"price":"1.1","quantity":"2"},{"price":"1.3","quantity":"4"}`,
+			`{"price":"0.9","quantity":"1"},{"price":"1.0","quantity":"3"},{"price":"0.8","quantity":"5"}`),
+		bookMessage(t, false, 8,
+			`{"price":"1.15","quantity":"0.5"}`,
+			`{"price":"1.05","quantity":"1"}`),
+		bookMessage(t, false, 9,
+			`{"price":"1.1","quantity":"0"},{"price":"1.3","quantity":"2"}`,
+			`{"price":"0.8","quantity":"0"},{"price":"0.7","quantity":"0"}`),
+	}
+	for _, msg := range messages {
+		if err := book.apply(msg); err != nil {
+			t.Fatalf("apply %d: %v", msg.Sequence, err)
+		}
+	}
+
+	snapshot, err := book.Snapshot(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Sequence != 9 {
+		t.Errorf("sequence = %d, want 9", snapshot.Sequence)
+	}
+	//zero quantities removed 1.1 and 0.8, and removing the missing 0.7 changed nothing
+	if got, want := levels(snapshot.Asks), "1.15:0.5 1.2:1 1.3:2"; got != want {
+		t.Errorf("asks = %s, want %s", got, want)
+	}
+	if got, want := levels(snapshot.Bids), "1.05:1 1.0:3 0.9:1"; got != want {
+		t.Errorf("bids = %s, want %s", got, want)
+	}
+
+	bid, ok := book.BestBid()
+	if !ok || bid.Price.String() != "1.05" {
+		t.Errorf("BestBid = %s, %t, want 1.05", bid.Price, ok)
+	}
+	ask, ok := book.BestAsk()
+	if !ok || ask.Price.String() != "1.15" {
+		t.Errorf("BestAsk = %s, %t, want 1.15", ask.Price, ok)
+	}
+
+	top, err := book.Snapshot(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := levels(top.Asks), "1.15:0.5 1.2:1"; got != want {
+		t.Errorf("top asks = %s, want %s", got, want)
+	}
+
+	//the snapshot is a copy, later updates do not change it
+	if err := book.apply(bookMessage(t, false, 10, `{"price":"1.15","quantity":"9"}`, ``)); err != nil {
+		t.Fatal(err)
+	}
+	if got := top.Asks[0].Quantity.String(); got != "0.5" {
+		t.Errorf("snapshot changed with the book, quantity = %s", got)
+	}
+}
+
+func TestLocalOrderBookGap(t *testing.T) {
+	book := &LocalOrderBook{Exchange: "binance", Pair: "ltc-btc"}
+
+	if _, err := book.Snapshot(0); !errors.Is(err, ErrBookNotSynced) {
+		t.Errorf("Snapshot before any message = %v, want ErrBookNotSynced", err)
+	}
+	//updates before the first snapshot are ignored
+	if err := book.apply(bookMessage(t, false, 3, `{"price":"5","quantity":"1"}`, ``)); err != nil {
+		t.Fatal(err)
+	}
+	if book.Synced() {
+		t.Fatal("synced without a snapshot")
+	}
+
+	if err := book.apply(bookMessage(t, true, 1, `{"price":"1.2","quantity":"1"}`, `{"price":"1.0","quantity":"1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.apply(bookMessage(t, false, 3, `{"price":"1.1","quantity":"1"}`, ``)); !errors.Is(err, ErrSequenceGap) {
+		t.Fatalf("apply after a gap = %v, want ErrSequenceGap", err)
+	}
+
+	if book.Synced() {
+		t.Error("still synced after a gap")
+	}
+	if _, ok := book.BestBid(); ok {
+		t.Error("BestBid answered on an unsynced book")
+	}
+	if _, err := book.Snapshot(0); !errors.Is(err, ErrBookNotSynced) {
+		t.Errorf("Snapshot = %v, want ErrBookNotSynced", err)
+	}
+	if _, err := book.BuyDepth(MustParseDecimal("1")); !errors.Is(err, ErrBookNotSynced) {
+		t.Errorf("BuyDepth = %v, want ErrBookNotSynced", err)
+	}
+
+	//updates are dropped until the next snapshot syncs the book again
+	if err := book.apply(bookMessage(t, false, 4, `{"price":"1.1","quantity":"1"}`, ``)); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.apply(bookMessage(t, true, 10, `{"price":"2","quantity":"1"}`, ``)); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := book.Snapshot(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := levels(snapshot.Asks); got != "2:1" {
+		t.Errorf("asks after resync = %s, want 2:1", got)
+	}
+	if got := levels(snapshot.Bids); got != "" {
+		t.Errorf("bids after resync = %s, want none", got)
+	}
+}
+
+func TestLocalOrderBookLoad(t *testing.T) {
+	book := &LocalOrderBook{Exchange: "binance", Pair: "ltc-btc"}
+	book.load(
+		[]OrderBookLevel{{MustParseDecimal("4"), MustParseDecimal("1")}, {MustParseDecimal("4.5"), MustParseDecimal("1")}},
+		[]OrderBookLevel{{MustParseDecimal("6"), MustParseDecimal("1")}, {MustParseDecimal("5"), MustParseDecimal("1")}},
+	)
+
+	//a rest snapshot has no sequence, so the next update is accepted whatever its number
+	if err := book.apply(bookMessage(t, false, 42, `{"price":"5","quantity":"0"}`, ``)); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.apply(bookMessage(t, false, 44, ``, ``)); !errors.Is(err, ErrSequenceGap) {
+		t.Errorf("apply after a gap = %v, want ErrSequenceGap", err)
+	}
+}
+
+func TestWalkDepth(t *testing.T) {
+	asks := []OrderBookLevel{
+		{MustParseDecimal("100"), MustParseDecimal("1")},
+		{MustParseDecimal("101"), MustParseDecimal("2")},
+	}
+
+	tests := []struct {
+		quantity string
+		want     Depth
+	}{
+		{"0.5", Depth{Quantity: MustParseDecimal("0.5"), Cost: MustParseDecimal("50"), WorstPrice: MustParseDecimal("100"), Levels: 1, Complete: true}},
+		{"2", Depth{Quantity: MustParseDecimal("2"), Cost: MustParseDecimal("201"), WorstPrice: MustParseDecimal("101"), Levels: 2, Complete: true}},
+		//the book only holds 3, so the walk stops short
+		{"5", Depth{Quantity: MustParseDecimal("3"), Cost: MustParseDecimal("302"), WorstPrice: MustParseDecimal("101"), Levels: 2, Complete: false}},
+	}
+
+	for _, tt := range tests {
+		d, err := walkDepth(asks, MustParseDecimal(tt.quantity))
+		if err != nil {
+			t.Errorf("walkDepth(%s) error: %v", tt.quantity, err)
+			continue
+		}
+		if d.Quantity.Cmp(tt.want.Quantity) != 0 || d.Cost.Cmp(tt.want.Cost) != 0 || d.WorstPrice.Cmp(tt.want.WorstPrice) != 0 ||
+			d.Levels != tt.want.Levels || d.Complete != tt.want.Complete {
+			t.Errorf("walkDepth(%s) = %+v, want %+v", tt.quantity, d, tt.want)
+		}
+		if average := d.Cost.Quo(d.Quantity, 18); d.AveragePrice.Cmp(average) != 0 {
+			t.Errorf("walkDepth(%s) average = %s, want %s", tt.quantity, d.AveragePrice, average)
+		}
+	}
+
+	d, err := walkDepth(nil, MustParseDecimal("1"))
+	if err != nil || d.Complete || d.Levels != 0 || !d.AveragePrice.IsZero() {
+		t.Errorf("walkDepth on an empty side = %+v, %v", d, err)
+	}
+	for _, quantity := range []string{"0", "-1"} {
+		if _, err := walkDepth(asks, MustParseDecimal(quantity)); err == nil {
+			t.Errorf("walkDepth(%s) accepted a quantity that is not positive", quantity)
+		}
+	}
+}
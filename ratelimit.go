@@ -0,0 +1,208 @@
+package shrimpygo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//EndpointCategory groups endpoints that share a shrimpy rate limit
+type EndpointCategory string
+
+const (
+	//CategoryPublic covers the exchange, asset and trading pair listings
+	CategoryPublic EndpointCategory = "public"
+	//CategoryMarket covers tickers, candles and order books
+	CategoryMarket EndpointCategory = "market"
+	//CategoryUser covers users, api keys, linked accounts and balances
+	CategoryUser EndpointCategory = "user"
+	//CategoryTrading covers trades, limit orders and anything else that places trades such as rebalancing
+	CategoryTrading EndpointCategory = "trading"
+	//CategoryHistorical covers the historical data endpoints
+	CategoryHistorical EndpointCategory = "historical"
+)
+
+//RateLimit is the request budget for one endpoint category
+type RateLimit struct {
+	//RequestsPerMinute is the sustained rate, zero or less means unlimited
+	RequestsPerMinute int
+	//Burst is how many requests may be sent back to back, one is used when unset
+	Burst int
+}
+
+//DefaultRateLimits returns conservative per-category limits, raise them to match the limits on your shrimpy plan
+func DefaultRateLimits() map[EndpointCategory]RateLimit {
+	return map[EndpointCategory]RateLimit{
+		CategoryPublic:     {RequestsPerMinute: 60, Burst: 10},
+		CategoryMarket:     {RequestsPerMinute: 60, Burst: 10},
+		CategoryUser:       {RequestsPerMinute: 60, Burst: 10},
+		CategoryTrading:    {RequestsPerMinute: 60, Burst: 5},
+		CategoryHistorical: {RequestsPerMinute: 60, Burst: 10},
+	}
+}
+
+//categorize works out which rate limit a request path falls under
+func categorize(requestPath string) EndpointCategory {
+	switch {
+	case strings.HasPrefix(requestPath, "/v1/historical/"):
+		return CategoryHistorical
+	case requestPath == "/v1/orderbooks",
+		strings.HasPrefix(requestPath, "/v1/exchanges/") && (strings.HasSuffix(requestPath, "/ticker") || strings.HasSuffix(requestPath, "/candles")):
+		return CategoryMarket
+	case requestPath == "/v1/list_exchanges", strings.HasPrefix(requestPath, "/v1/exchanges/"):
+		return CategoryPublic
+	case strings.Contains(requestPath, "/trades"), strings.Contains(requestPath, "/orders"),
+		strings.HasSuffix(requestPath, "/rebalance"), strings.HasSuffix(requestPath, "/allocate"):
+		return CategoryTrading
+	default:
+		return CategoryUser
+	}
+}
+
+//rateLimiter holds a token bucket per endpoint category
+type rateLimiter struct {
+	buckets map[EndpointCategory]*tokenBucket
+}
+
+//newRateLimiter builds the limiter for a client, nil when rate limiting is disabled
+func newRateLimiter(config Config) *rateLimiter {
+	if config.DisableRateLimit {
+		return nil
+	}
+
+	limits := config.RateLimits
+	if limits == nil {
+		limits = DefaultRateLimits()
+	}
+
+	limiter := &rateLimiter{buckets: make(map[EndpointCategory]*tokenBucket)}
+	for category, limit := range limits {
+		if limit.RequestsPerMinute <= 0 {
+			continue
+		}
+
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+
+		limiter.buckets[category] = &tokenBucket{
+			rate:   float64(limit.RequestsPerMinute) / 60,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+	}
+
+	return limiter
+}
+
+//wait blocks until a request in this category may be sent or ctx is done
+func (limiter *rateLimiter) wait(ctx context.Context, category EndpointCategory) error {
+	if limiter == nil || limiter.buckets[category] == nil {
+		return nil
+	}
+	return limiter.buckets[category].wait(ctx)
+}
+
+//observe adapts the bucket for this category to the rate limit headers on a response
+func (limiter *rateLimiter) observe(category EndpointCategory, resp *http.Response) {
+	if limiter == nil || limiter.buckets[category] == nil {
+		return
+	}
+	limiter.buckets[category].observe(resp)
+}
+
+//tokenBucket is a classic token bucket refilled continuously at rate tokens per second
+type tokenBucket struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+//refill tops up tokens for the time passed since the last call. Callers hold mu
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.refill(now)
+
+		var delay time.Duration
+		switch {
+		case now.Before(b.blockedUntil):
+			delay = b.blockedUntil.Sub(now)
+		case b.tokens >= 1:
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		default:
+			delay = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		}
+		b.mu.Unlock()
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+//observe reads X-RateLimit-Remaining / X-RateLimit-Reset and Retry-After when shrimpy sends them
+func (b *tokenBucket) observe(resp *http.Response) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.refill(now)
+
+	if remaining, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Remaining"), 64); err == nil {
+		if remaining < b.tokens {
+			b.tokens = remaining
+		}
+
+		if remaining < 1 {
+			if reset := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now); reset.After(b.blockedUntil) {
+				b.blockedUntil = reset
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		b.tokens = 0
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if wait <= 0 {
+			wait = time.Duration(float64(time.Second) / b.rate)
+		}
+
+		if until := now.Add(wait); until.After(b.blockedUntil) {
+			b.blockedUntil = until
+		}
+	}
+}
+
+//parseRateLimitReset accepts either seconds until the reset or a unix timestamp in seconds
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+
+	if seconds > 1000000000 {
+		return time.Unix(seconds, 0)
+	}
+
+	return now.Add(time.Duration(seconds) * time.Second)
+}
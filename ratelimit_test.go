@@ -0,0 +1,205 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		path string
+		want EndpointCategory
+	}{
+		{"/v1/list_exchanges", CategoryPublic},
+		{"/v1/exchanges/binance/assets", CategoryPublic},
+		{"/v1/exchanges/binance/trading_pairs", CategoryPublic},
+
+		{"/v1/exchanges/binance/ticker", CategoryMarket},
+		{"/v1/exchanges/binance/candles", CategoryMarket},
+		{"/v1/orderbooks", CategoryMarket},
+
+		{"/v1/historical/instruments", CategoryHistorical},
+		{"/v1/historical/candles", CategoryHistorical},
+		{"/v1/historical/trades", CategoryHistorical},
+		{"/v1/historical/orderbooks", CategoryHistorical},
+		{"/v1/historical/count", CategoryHistorical},
+
+		{"/v1/users", CategoryUser},
+		{"/v1/users/u1", CategoryUser},
+		{"/v1/users/u1/name", CategoryUser},
+		{"/v1/users/u1/keys/pk/permissions", CategoryUser},
+		{"/v1/users/u1/accounts", CategoryUser},
+		{"/v1/users/u1/accounts/42/balance", CategoryUser},
+		{"/v1/users/u1/accounts/42/total_balance_history", CategoryUser},
+		{"/v1/users/u1/accounts/42/rebalance_period", CategoryUser},
+		{"/v1/users/u1/accounts/42/strategy", CategoryUser},
+		{"/v1/users/u1/whitelist", CategoryUser},
+		{"/v1/accounts", CategoryUser},
+		{"/v1/accounts/42", CategoryUser},
+		{"/v1/accounts/42/balance", CategoryUser},
+		{"/v1/accounts/42/total_balance_history", CategoryUser},
+		{"/v1/ws/token", CategoryUser},
+		{"/v1/management/usage", CategoryUser},
+		{"/v1/analytics/backtest/binance/assets", CategoryUser},
+		{"/v1/analytics/backtest/run", CategoryUser},
+		{"/v1/insights/asset_dominance", CategoryUser},
+		{"/v1/insights/asset_popularity", CategoryUser},
+
+		{"/v1/users/u1/accounts/42/trades", CategoryTrading},
+		{"/v1/users/u1/accounts/42/trades/t1", CategoryTrading},
+		{"/v1/users/u1/accounts/42/orders", CategoryTrading},
+		{"/v1/users/u1/accounts/42/orders/o1", CategoryTrading},
+		{"/v1/users/u1/accounts/42/rebalance", CategoryTrading},
+		{"/v1/users/u1/accounts/42/allocate", CategoryTrading},
+		{"/v1/accounts/42/trades", CategoryTrading},
+		{"/v1/accounts/42/orders/o1", CategoryTrading},
+	}
+
+	for _, tt := range tests {
+		if got := categorize(tt.path); got != tt.want {
+			t.Errorf("categorize(%s) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+//newTestBucket returns the bucket a limiter builds for one category with this limit
+func newTestBucket(t *testing.T, limit RateLimit) (*rateLimiter, *tokenBucket) {
+	t.Helper()
+
+	limiter := newRateLimiter(Config{RateLimits: map[EndpointCategory]RateLimit{CategoryUser: limit}})
+	bucket := limiter.buckets[CategoryUser]
+	if bucket == nil {
+		t.Fatal("no bucket for the user category")
+	}
+	return limiter, bucket
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter, _ := newTestBucket(t, RateLimit{RequestsPerMinute: 600, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(ctx, CategoryUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 3 took %s", elapsed)
+	}
+
+	//the bucket is empty, the fourth waits for a token at 10 a second
+	start = time.Now()
+	if err := limiter.wait(ctx, CategoryUser); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("fourth request waited %s, want about 100ms", elapsed)
+	}
+
+	//categories without a limit are never held up
+	for i := 0; i < 10; i++ {
+		if err := limiter.wait(ctx, CategoryTrading); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	limiter, _ := newTestBucket(t, RateLimit{RequestsPerMinute: 1, Burst: 1})
+	if err := limiter.wait(context.Background(), CategoryUser); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := limiter.wait(ctx, CategoryUser); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled wait took %s", elapsed)
+	}
+}
+
+func rateLimitResponse(statusCode int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: statusCode, Header: make(http.Header)}
+	for name, value := range headers {
+		resp.Header.Set(name, value)
+	}
+	return resp
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	resetAt := time.Now().Add(30 * time.Second).Unix()
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		tokens  float64
+		blocked time.Duration
+	}{
+		{"no headers", rateLimitResponse(http.StatusOK, nil), 10, 0},
+		{"remaining", rateLimitResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "4"}), 4, 0},
+		{"remaining above tokens", rateLimitResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "50"}), 10, 0},
+		{"used up, reset in seconds", rateLimitResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "5"}), 0, 5 * time.Second},
+		{"used up, reset as a timestamp", rateLimitResponse(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(resetAt, 10)}), 0, time.Until(time.Unix(resetAt, 0))},
+		{"429 with Retry-After", rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "7"}), 0, 7 * time.Second},
+		{"429 without Retry-After", rateLimitResponse(http.StatusTooManyRequests, nil), 0, 6 * time.Second},
+	}
+
+	for _, tt := range tests {
+		limiter, bucket := newTestBucket(t, RateLimit{RequestsPerMinute: 10, Burst: 10})
+		limiter.observe(CategoryUser, tt.resp)
+
+		bucket.mu.Lock()
+		tokens, blockedUntil := bucket.tokens, bucket.blockedUntil
+		bucket.mu.Unlock()
+
+		if tokens < tt.tokens-0.01 || tokens > tt.tokens+0.01 {
+			t.Errorf("%s: tokens = %.2f, want %.0f", tt.name, tokens, tt.tokens)
+		}
+
+		if tt.blocked == 0 {
+			if !blockedUntil.IsZero() {
+				t.Errorf("%s: blocked until %s, want not blocked", tt.name, blockedUntil)
+			}
+			continue
+		}
+		if blocked := time.Until(blockedUntil); blocked < tt.blocked-2*time.Second || blocked > tt.blocked+time.Second {
+			t.Errorf("%s: blocked for %s, want %s", tt.name, blocked, tt.blocked)
+		}
+	}
+}
+
+func TestRateLimiterBlocked(t *testing.T) {
+	limiter, _ := newTestBucket(t, RateLimit{RequestsPerMinute: 600, Burst: 10})
+
+	//a 429 holds every request back until the Retry-After, however many tokens were left
+	limiter.observe(CategoryUser, rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "1"}))
+
+	start := time.Now()
+	if err := limiter.wait(context.Background(), CategoryUser); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("request after a 429 waited %s, want the 1s Retry-After", elapsed)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	if limiter := newRateLimiter(Config{DisableRateLimit: true}); limiter != nil {
+		t.Fatal("DisableRateLimit built a limiter")
+	}
+
+	var limiter *rateLimiter
+	if err := limiter.wait(context.Background(), CategoryUser); err != nil {
+		t.Errorf("nil limiter wait = %v", err)
+	}
+	limiter.observe(CategoryUser, rateLimitResponse(http.StatusTooManyRequests, nil))
+}
@@ -0,0 +1,117 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+//RetryPolicy controls how failed GET requests are retried.
+//Only GETs are retried: a POST such as CreateTrade or PlaceLimitOrder may have reached the
+//exchange before the failure, so sending it again could place the order twice.
+type RetryPolicy struct {
+	//MaxAttempts is the total number of tries including the first, anything below 2 disables retries
+	MaxAttempts int
+	//InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+	//MaxBackoff caps the wait between any two tries
+	MaxBackoff time.Duration
+	//Multiplier grows the wait after every retry, 2 is used when unset
+	Multiplier float64
+	//Jitter is the fraction of each wait that is randomised, between 0 and 1
+	Jitter float64
+}
+
+//DefaultRetryPolicy returns a policy suitable for batch jobs: four tries over a few seconds
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+//backoff returns how long to wait before the given retry, retry 1 being the first
+func (policy *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	wait := float64(policy.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		wait *= multiplier
+		if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+			break
+		}
+	}
+
+	if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+		wait = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter > 0 {
+		jitter := policy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		wait -= wait * jitter * rand.Float64()
+	}
+
+	return time.Duration(wait)
+}
+
+//retryable reports whether err is worth another try: network failures, 429 and 5xx responses
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.transport && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return false
+}
+
+//doWithRetry sends the request, retrying GETs according to Config.Retry.
+//Every try goes back through httpDo so it is signed with a fresh nonce.
+func (client *Client) doWithRetry(ctx context.Context, span CallSpan, method string, param string, requestPath string, requestBody string) ([]byte, error) {
+	policy := client.Config.Retry
+	for attempt := 1; ; attempt++ {
+		body, err := client.httpDo(ctx, span, attempt, method, param, requestPath, requestBody)
+		if err == nil || method != GET || policy == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return body, err
+		}
+
+		wait := policy.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		client.logRetry(ctx, method, requestPath, attempt, wait, err)
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, &RequestError{Method: method, Path: requestPath, Err: err}
+		}
+	}
+}
+
+//sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//newRetryClient returns a client with retries on for a server that runs fail on every try until it returns false
+func newRetryClient(t *testing.T, retry *RetryPolicy, fail func(w http.ResponseWriter, r *http.Request, try int32) bool) (*Client, *int32) {
+	t.Helper()
+
+	var tries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail(w, r, atomic.AddInt32(&tries, 1)) {
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(Config{
+		Endpoint:         server.URL,
+		MasterAPIKey:     "key",
+		MasterSecretKey:  "c2VjcmV0",
+		DisableRateLimit: true,
+		Retry:            retry,
+	})
+	return client, &tries
+}
+
+func TestRetryGet(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(w http.ResponseWriter)
+	}{
+		{"502", func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) }},
+		{"429", func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) }},
+		{"transport error", func(w http.ResponseWriter) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		client, tries := newRetryClient(t, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func(w http.ResponseWriter, r *http.Request, try int32) bool {
+			if try < 3 {
+				tt.fail(w)
+				return true
+			}
+			return false
+		})
+
+		if _, err := client.GetExchangeTickers(context.Background(), "binance"); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if got := atomic.LoadInt32(tries); got != 3 {
+			t.Errorf("%s: %d tries, want 3", tt.name, got)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, tries := newRetryClient(t, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func(w http.ResponseWriter, r *http.Request, try int32) bool {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return true
+	})
+
+	_, err := client.GetExchangeTickers(context.Background(), "binance")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("error = %v, want the last 503", err)
+	}
+	if got := atomic.LoadInt32(tries); got != 3 {
+		t.Errorf("%d tries, want 3", got)
+	}
+}
+
+func TestRetryNotRetried(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(client *Client) error
+		fail func(w http.ResponseWriter)
+	}{
+		{"POST", func(client *Client) error {
+			_, err := client.SubmitTrade(context.Background(), "701e0d16-1e9e-42c9-b6a1-4cada1f395b8", "123", CreateTradeRequest{
+				FromSymbol: "BTC",
+				ToSymbol:   "ETH",
+				Amount:     MustParseDecimal("0.01"),
+			})
+			return err
+		}, func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) }},
+		{"404", func(client *Client) error {
+			_, err := client.GetExchangeTickers(context.Background(), "binance")
+			return err
+		}, func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) }},
+	}
+
+	for _, tt := range tests {
+		client, tries := newRetryClient(t, &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond}, func(w http.ResponseWriter, r *http.Request, try int32) bool {
+			tt.fail(w)
+			return true
+		})
+
+		if err := tt.call(client); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		if got := atomic.LoadInt32(tries); got != 1 {
+			t.Errorf("%s: %d tries, want 1", tt.name, got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	client, tries := newRetryClient(t, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}, func(w http.ResponseWriter, r *http.Request, try int32) bool {
+		if try == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return true
+		}
+		return false
+	})
+
+	start := time.Now()
+	if _, err := client.GetExchangeTickers(context.Background(), "binance"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s Retry-After", elapsed)
+	}
+	if got := atomic.LoadInt32(tries); got != 2 {
+		t.Errorf("%d tries, want 2", got)
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	client, tries := newRetryClient(t, &RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Hour}, func(w http.ResponseWriter, r *http.Request, try int32) bool {
+		w.WriteHeader(http.StatusBadGateway)
+		return true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetExchangeTickers(ctx, "binance")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %s, the backoff was not cut short", elapsed)
+	}
+	if got := atomic.LoadInt32(tries); got != 1 {
+		t.Errorf("%d tries, want 1", got)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 300 * time.Millisecond},
+		{3, 900 * time.Millisecond},
+		{4, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.retry, got, tt.want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(2); got < 150*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("backoff(2) with jitter = %s, want between 150ms and 300ms", got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+//Package shrimpyotel traces shrimpygo api calls with OpenTelemetry.
+//It lives in its own package so programs that do not trace never compile the otel modules.
+package shrimpyotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	shrimpygo "github.com/ashman1984/shrimpy-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//instrumentationName is the scope spans are created under
+const instrumentationName = "github.com/ashman1984/shrimpy-go/shrimpyotel"
+
+//Tracer creates an OpenTelemetry client span for every api call, set it as shrimpygo.Config.Tracer
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+//NewTracer returns a tracer creating spans from provider and sending the trace context with propagator.
+//The global otel tracer provider and propagator are used for whichever is nil.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	return &Tracer{tracer: provider.Tracer(instrumentationName), propagator: propagator}
+}
+
+//StartCall starts a client span named after the call's route, a child of any span in ctx
+func (tracer *Tracer) StartCall(ctx context.Context, call shrimpygo.CallInfo) (context.Context, shrimpygo.CallSpan) {
+	attrs := []attribute.KeyValue{
+		attribute.String("shrimpy.category", string(call.Category)),
+		attribute.String("http.request.method", call.Method),
+		attribute.String("url.template", call.Route),
+	}
+	if call.Exchange != "" {
+		attrs = append(attrs, attribute.String("shrimpy.exchange", call.Exchange))
+	}
+	if call.UserIDHash != "" {
+		attrs = append(attrs, attribute.String("shrimpy.user_id_hash", call.UserIDHash))
+	}
+
+	ctx, span := tracer.tracer.Start(ctx, "shrimpy "+call.Method+" "+call.Route, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, &callSpan{ctx: ctx, span: span, propagator: tracer.propagator, route: call.Route}
+}
+
+//callSpan is the span of one call. ctx holds the span for injecting, whatever hooks do to the context of each try.
+type callSpan struct {
+	ctx        context.Context
+	span       trace.Span
+	propagator propagation.TextMapPropagator
+	route      string
+	//path is the raw request path seen on the last try, replaced by route in error messages
+	path string
+}
+
+//Inject writes the call span's trace context into the request headers
+func (call *callSpan) Inject(header http.Header) {
+	call.propagator.Inject(call.ctx, propagation.HeaderCarrier(header))
+}
+
+//Try keeps the last try's status code and retry count and adds an event for every failed try
+func (call *callSpan) Try(info shrimpygo.ResponseInfo) {
+	call.path = info.Path
+
+	call.span.SetAttributes(attribute.Int("shrimpy.retry_count", info.Attempt-1))
+	if info.StatusCode != 0 {
+		call.span.SetAttributes(attribute.Int("http.response.status_code", info.StatusCode))
+	}
+	if info.Err != nil {
+		call.span.AddEvent("shrimpy.try_failed", trace.WithAttributes(
+			attribute.Int("shrimpy.attempt", info.Attempt),
+			attribute.String("error.message", call.redact(info.Err)),
+		))
+	}
+}
+
+//End ends the span, marking it failed when err is set
+func (call *callSpan) End(err error) {
+	if err != nil {
+		message := call.redact(err)
+		call.span.RecordError(errors.New(message))
+		call.span.SetStatus(codes.Error, message)
+	}
+	call.span.End()
+}
+
+//redact replaces the request path in an error message with the route, errors name the path and it holds user and account ids
+func (call *callSpan) redact(err error) string {
+	if call.path == "" {
+		return err.Error()
+	}
+	return strings.ReplaceAll(err.Error(), call.path, call.route)
+}
@@ -0,0 +1,225 @@
+package shrimpyotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	shrimpygo "github.com/ashman1984/shrimpy-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testUserID = "701e0d16-1e9e-42c9-b6a1-4cada1f395b8"
+
+//newTestClient returns a client sending to handler, traced into an in-memory exporter
+func newTestClient(t *testing.T, handler http.HandlerFunc, hooks ...shrimpygo.Hooks) (*shrimpygo.Client, *sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client := shrimpygo.NewClient(shrimpygo.Config{
+		Endpoint:         server.URL,
+		MasterAPIKey:     "key",
+		MasterSecretKey:  "c2VjcmV0",
+		DisableRateLimit: true,
+		Retry:            &shrimpygo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		Hooks:            hooks,
+		Tracer:           NewTracer(provider, propagation.TraceContext{}),
+	})
+	return client, provider, exporter
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, prefix string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if strings.HasPrefix(span.Name, prefix) {
+			return span
+		}
+	}
+	t.Fatalf("no span named %s*, got %d spans", prefix, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func TestCallSpan(t *testing.T) {
+	var tries int32
+	var traceparent atomic.Value
+	client, provider, exporter := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("traceparent"))
+		if atomic.AddInt32(&tries, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "rebalance")
+	if _, err := client.GetActiveTrades(ctx, testUserID, "42"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	span := findSpan(t, exporter.GetSpans(), "shrimpy ")
+	if span.Name != "shrimpy GET /v1/users/{userID}/accounts/{accountID}/trades" {
+		t.Errorf("span name = %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span kind = %v", span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("span is not a child of the caller's span")
+	}
+
+	attrs := attributes(span)
+	if got := attrs["shrimpy.category"].AsString(); got != string(shrimpygo.CategoryTrading) {
+		t.Errorf("shrimpy.category = %q", got)
+	}
+	if got := attrs["shrimpy.user_id_hash"].AsString(); got == "" || got == testUserID {
+		t.Errorf("shrimpy.user_id_hash = %q", got)
+	}
+	if got := attrs["shrimpy.retry_count"].AsInt64(); got != 1 {
+		t.Errorf("shrimpy.retry_count = %d, want 1", got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusOK {
+		t.Errorf("http.response.status_code = %d, want 200", got)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "shrimpy.try_failed" {
+		t.Errorf("events = %+v, want one shrimpy.try_failed", span.Events)
+	}
+	if span.Status.Code == codes.Error {
+		t.Errorf("status = %+v, want unset", span.Status)
+	}
+
+	header, _ := traceparent.Load().(string)
+	if !strings.Contains(header, span.SpanContext.TraceID().String()) || !strings.Contains(header, span.SpanContext.SpanID().String()) {
+		t.Errorf("traceparent %q does not carry the call span", header)
+	}
+}
+
+func TestCallSpanExchange(t *testing.T) {
+	client, _, exporter := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+
+	if _, err := client.GetExchangeTickers(context.Background(), "binance"); err != nil {
+		t.Fatal(err)
+	}
+
+	span := findSpan(t, exporter.GetSpans(), "shrimpy ")
+	attrs := attributes(span)
+	if got := attrs["shrimpy.exchange"].AsString(); got != "binance" {
+		t.Errorf("shrimpy.exchange = %q", got)
+	}
+	if _, ok := attrs["shrimpy.user_id_hash"]; ok {
+		t.Errorf("market data call has a user id hash")
+	}
+	if span.Parent.SpanID().IsValid() {
+		t.Errorf("call without a parent span got parent %s", span.Parent.SpanID())
+	}
+}
+
+func TestCallSpanError(t *testing.T) {
+	client, _, exporter := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"account not found"}`))
+	})
+
+	if _, err := client.GetActiveTrades(context.Background(), testUserID, "42"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	span := findSpan(t, exporter.GetSpans(), "shrimpy ")
+	if span.Status.Code != codes.Error {
+		t.Errorf("status = %+v, want error", span.Status)
+	}
+	if got := attributes(span)["http.response.status_code"].AsInt64(); got != http.StatusNotFound {
+		t.Errorf("http.response.status_code = %d, want 404", got)
+	}
+
+	//the error names the request path, which must not put the user id in the trace
+	if strings.Contains(span.Status.Description, testUserID) {
+		t.Errorf("status description leaks the user id: %s", span.Status.Description)
+	}
+	for _, event := range span.Events {
+		for _, kv := range event.Attributes {
+			if strings.Contains(kv.Value.Emit(), testUserID) {
+				t.Errorf("event %s leaks the user id: %s", event.Name, kv.Value.Emit())
+			}
+		}
+	}
+}
+
+func TestCallSpanWithHookSpans(t *testing.T) {
+	type hookSpanKey struct{}
+
+	var hookTracer trace.Tracer
+	hooks := shrimpygo.HookFuncs{
+		BeforeRequestFunc: func(ctx context.Context, info shrimpygo.RequestInfo) context.Context {
+			ctx, span := hookTracer.Start(ctx, "hook try")
+			return context.WithValue(ctx, hookSpanKey{}, span)
+		},
+		AfterResponseFunc: func(ctx context.Context, info shrimpygo.ResponseInfo) {
+			ctx.Value(hookSpanKey{}).(trace.Span).End()
+		},
+	}
+
+	var tries int32
+	client, provider, exporter := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&tries, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}, hooks)
+	hookTracer = provider.Tracer("hooks")
+
+	if _, err := client.GetActiveTrades(context.Background(), testUserID, "42"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	call := findSpan(t, spans, "shrimpy ")
+	if got := attributes(call)["shrimpy.retry_count"].AsInt64(); got != 1 {
+		t.Errorf("call span shrimpy.retry_count = %d, want 1", got)
+	}
+	if len(call.Events) != 1 {
+		t.Errorf("call span has %d events, want 1", len(call.Events))
+	}
+
+	hookSpans := 0
+	for _, span := range spans {
+		if span.Name != "hook try" {
+			continue
+		}
+		hookSpans++
+		if span.Parent.SpanID() != call.SpanContext.SpanID() {
+			t.Errorf("hook span is not a child of the call span")
+		}
+		if _, ok := attributes(span)["shrimpy.retry_count"]; ok || len(span.Events) > 0 {
+			t.Errorf("call attributes landed on the hook's span: %+v", span.Attributes)
+		}
+	}
+	if hookSpans != 2 {
+		t.Errorf("got %d hook spans, want 2", hookSpans)
+	}
+}
@@ -1,304 +1,646 @@
-package shrimpygo
-
-import (
-	"time"
-)
-
-//Client is the connection
-type Client struct {
-	Config Config
-}
-
-//Config for the client to work
-type Config struct {
-	Endpoint        string
-	MasterAPIKey    string
-	MasterSecretKey string
-	DebugMessages   bool
-}
-
-//SupportedExchanges gets all exchanges that shrimpy suppports
-type SupportedExchanges []struct {
-	Exchange     string  `json:"exchange"`
-	BestCaseFee  float64 `json:"bestCaseFee"`
-	WorstCaseFee float64 `json:"worstCaseFee"`
-	Icon         string  `json:"icon"`
-}
-
-//Assets are coin objects for an exchange
-type Assets []struct {
-	ID            int    `json:"id"`
-	Name          string `json:"name"`
-	Symbol        string `json:"symbol"`
-	TradingSymbol string `json:"tradingSymbol"`
-}
-
-//Pairs are coin pairs for an exchange
-type Pairs []struct {
-	BaseTradingSymbol  string `json:"baseTradingSymbol"`
-	QuoteTradingSymbol string `json:"quoteTradingSymbol"`
-}
-
-//Tickers are tickers for an exchange
-type Tickers []struct {
-	Name                string    `json:"name"`
-	Symbol              string    `json:"symbol"`
-	PriceUsd            string    `json:"priceUsd"`
-	PriceBtc            string    `json:"priceBtc"`
-	PercentChange24HUsd string    `json:"percentChange24hUsd"`
-	LastUpdated         time.Time `json:"lastUpdated"`
-}
-
-//ExchangeOrders - list of exchange objects which contains orderbooks
-type ExchangeOrders []struct {
-	QuoteSymbol string `json:"quoteSymbol"`
-	BaseSymbol  string `json:"baseSymbol"`
-	OrderBooks  []struct {
-		Exchange  string `json:"exchange"`
-		OrderBook struct {
-			Asks []struct {
-				Price    string `json:"price"`
-				Quantity string `json:"quantity"`
-			} `json:"asks"`
-			Bids []struct {
-				Price    string `json:"price"`
-				Quantity string `json:"quantity"`
-			} `json:"bids"`
-		} `json:"orderBook"`
-	} `json:"orderBooks"`
-}
-
-//CandleSticks hold all candle sticks for requested period
-type CandleSticks []struct {
-	Open        string    `json:"open"`
-	High        string    `json:"high"`
-	Low         string    `json:"low"`
-	Close       string    `json:"close"`
-	Volume      string    `json:"volume"`
-	QuoteVolume float64   `json:"quoteVolume"`
-	BtcVolume   float64   `json:"btcVolume"`
-	UsdVolume   float64   `json:"usdVolume"`
-	Time        time.Time `json:"time"`
-}
-
-//UsersList defines a list of users associated with this masterAPI key
-type UsersList []struct {
-	ExpirationDate time.Time `json:"expirationDate"`
-	ID             string    `json:"id"`
-	IsEnabled      bool      `json:"isEnabled"`
-	Name           string    `json:"name"`
-}
-
-//SingleUser defines a single user object
-type SingleUser struct {
-	ExpirationDate time.Time `json:"expirationDate"`
-	ID             string    `json:"id"`
-	IsEnabled      bool      `json:"isEnabled"`
-	Name           string    `json:"name"`
-}
-
-//UserID of new user
-type UserID struct {
-	ID string `json:"id"`
-}
-
-//CreateUserRequest optional field creating new user
-type CreateUserRequest struct {
-	Name string `json:"name"`
-}
-
-//SuccessReturn returns whether the operation was successful
-type SuccessReturn struct {
-	Success bool `json:"success"`
-}
-
-//GetPublicAPIKeys returns all public keys associated with this user
-type GetPublicAPIKeys []string
-
-//WhitelistedIPs returns all whitelisted IPS associated with this account
-type WhitelistedIPs []string
-
-//CreateAPIKeyReturn creates new keys for this user
-type CreateAPIKeyReturn struct {
-	PublicKey  string `json:"publicKey"`
-	PrivateKey string `json:"privateKey"`
-}
-
-//APIKeyPermissions holds shrimpy permissions for a particular public key
-type APIKeyPermissions struct {
-	Account bool `json:"account"`
-	Trade   bool `json:"trade"`
-}
-
-//LinkedAccounts for storing linked account data
-type LinkedAccounts []struct {
-	ID                int           `json:"id"`
-	Exchange          string        `json:"exchange"`
-	IsRebalancing     bool          `json:"isRebalancing"`
-	ExchangeAPIErrors []interface{} `json:"exchangeApiErrors"`
-}
-
-//LinkedExchangeAccount for storing exchange account data
-type LinkedExchangeAccount struct {
-	ID                int           `json:"id"`
-	Exchange          string        `json:"exchange"`
-	IsRebalancing     bool          `json:"isRebalancing"`
-	ExchangeAPIErrors []interface{} `json:"exchangeApiErrors"`
-}
-
-//LinkAccountRequest is the data we send to link an account to a user
-type LinkAccountRequest struct {
-	Exchange   string `json:"exchange"`
-	PublicKey  string `json:"publicKey"`
-	PrivateKey string `json:"privateKey"`
-	Passphrase string `json:"passphrase,omitempty"`
-}
-
-//LinkAccountResponse is the responding id for the account that was just linked
-type LinkAccountResponse struct {
-	ID int `json:"id"`
-}
-
-//CreateTradeResponse stores the trade id of a posted trade
-type CreateTradeResponse struct {
-	ID string `json:"id"`
-}
-
-//CreateTradeRequest is the body for a create trade request
-type CreateTradeRequest struct {
-	FromSymbol         string `json:"fromSymbol"`
-	ToSymbol           string `json:"toSymbol"`
-	Amount             string `json:"amount"`
-	SmartRouting       bool   `json:"smartRouting,omitempty"`
-	MaxSpreadPercent   string `json:"maxSpreadPercent,omitempty"`
-	MaxSlippagePercent string `json:"maxSlippagePercent,omitempty"`
-}
-
-//TradeStatus holds info from requesting status of a particular trade
-type TradeStatus struct {
-	Trade struct {
-		ID                   string        `json:"id"`
-		FromSymbol           string        `json:"fromSymbol"`
-		ToSymbol             string        `json:"toSymbol"`
-		Amount               string        `json:"amount"`
-		Status               string        `json:"status"`
-		Success              bool          `json:"success"`
-		ErrorCode            int           `json:"errorCode"`
-		ErrorMessage         string        `json:"errorMessage"`
-		ExchangeAPIErrors    []interface{} `json:"exchangeApiErrors"`
-		SmartRouting         bool          `json:"smartRouting"`
-		MaxSpreadPercent     string        `json:"maxSpreadPercent"`
-		MaxSlippagePercent   string        `json:"maxSlippagePercent"`
-		TriggeredMaxSpread   bool          `json:"triggeredMaxSpread"`
-		TriggeredMaxSlippage bool          `json:"triggeredMaxSlippage"`
-	} `json:"trade"`
-	Changes []struct {
-		Symbol      string  `json:"symbol"`
-		NativeValue string  `json:"nativeValue"`
-		BtcValue    float64 `json:"btcValue"`
-		UsdValue    float64 `json:"usdValue"`
-	} `json:"changes"`
-	Fills []struct {
-		BaseAmount  string  `json:"baseAmount"`
-		BaseSymbol  string  `json:"baseSymbol"`
-		BtcValue    float64 `json:"btcValue"`
-		Price       string  `json:"price"`
-		QuoteAmount string  `json:"quoteAmount"`
-		QuoteSymbol string  `json:"quoteSymbol"`
-		Side        string  `json:"side"`
-		UsdValue    float64 `json:"usdValue"`
-	} `json:"fills"`
-}
-
-//ActiveTrades holds an array of active trades associated with a useraccount / exchange
-type ActiveTrades []struct {
-	ID                   string        `json:"id"`
-	FromSymbol           string        `json:"fromSymbol"`
-	ToSymbol             string        `json:"toSymbol"`
-	Amount               float64       `json:"amount"`
-	Status               string        `json:"status"`
-	Success              bool          `json:"success"`
-	ErrorCode            int           `json:"errorCode"`
-	ErrorMessage         string        `json:"errorMessage"`
-	ExchangeAPIErrors    []interface{} `json:"exchangeApiErrors"`
-	SmartRouting         bool          `json:"smartRouting"`
-	MaxSpreadPercent     string        `json:"maxSpreadPercent"`
-	MaxSlippagePercent   string        `json:"maxSlippagePercent"`
-	TriggeredMaxSpread   bool          `json:"triggeredMaxSpread"`
-	TriggeredMaxSlippage bool          `json:"triggeredMaxSlippage"`
-}
-
-//TotalBalanceHistory holds aggregate balance data
-type TotalBalanceHistory []struct {
-	Date     time.Time `json:"date"`
-	UsdValue float64   `json:"usdValue"`
-	BtcValue float64   `json:"btcValue"`
-}
-
-//LimitOrderRequest holds data for a limit order request
-type LimitOrderRequest struct {
-	BaseSymbol  string `json:"baseSymbol"`
-	QuoteSymbol string `json:"quoteSymbol"`
-	Quantity    string `json:"quantity"`
-	Price       string `json:"price"`
-	Side        string `json:"side"`
-	TimeInForce string `json:"timeInForce"`
-}
-
-//LimitOrderReturn returns ID of order
-type LimitOrderReturn struct {
-	ID string `json:"id"`
-}
-
-//LimitOrderStatusReturn holds a particular executed orders information
-type LimitOrderStatusReturn struct {
-	Order struct {
-		ID                string        `json:"id"`
-		BaseSymbol        string        `json:"baseSymbol"`
-		QuoteSymbol       string        `json:"quoteSymbol"`
-		Amount            string        `json:"amount"`
-		Price             string        `json:"price"`
-		Side              string        `json:"side"`
-		TimeInForce       string        `json:"timeInForce"`
-		Status            string        `json:"status"`
-		CancelRequested   bool          `json:"cancelRequested"`
-		Success           bool          `json:"success"`
-		ErrorCode         int           `json:"errorCode"`
-		ErrorMessage      string        `json:"errorMessage"`
-		ExchangeAPIErrors []interface{} `json:"exchangeApiErrors"`
-	} `json:"order"`
-	Changes []struct {
-		Symbol      string  `json:"symbol"`
-		NativeValue string  `json:"nativeValue"`
-		BtcValue    float64 `json:"btcValue"`
-		UsdValue    float64 `json:"usdValue"`
-	} `json:"changes"`
-}
-
-//OpenActiveOrders holds all not in 'completed' state orders
-type OpenActiveOrders []struct {
-	Amount            string        `json:"amount"`
-	Price             string        `json:"price"`
-	CancelRequested   bool          `json:"cancelRequested"`
-	ErrorCode         int           `json:"errorCode"`
-	ErrorMessage      string        `json:"errorMessage"`
-	BaseSymbol        string        `json:"baseSymbol"`
-	QuoteSymbol       string        `json:"quoteSymbol"`
-	ID                string        `json:"id"`
-	Status            string        `json:"status"`
-	Success           bool          `json:"success"`
-	Side              string        `json:"side"`
-	TimeInForce       string        `json:"timeInForce"`
-	ExchangeAPIErrors []interface{} `json:"exchangeApiErrors"`
-}
-
-//ExchangeBalances keeps all balances of each coin
-type ExchangeBalances struct {
-	RetrievedAt time.Time `json:"retrievedAt"`
-	Balances    []struct {
-		Symbol      string  `json:"symbol"`
-		NativeValue float64 `json:"nativeValue"`
-		BtcValue    float64 `json:"btcValue"`
-		UsdValue    float64 `json:"usdValue"`
-	} `json:"balances"`
-}
+package shrimpygo
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Client is the connection
+type Client struct {
+	Config     Config
+	httpClient *http.Client
+	nonce      NonceSource
+	nonceOnce  sync.Once
+	limiter    *rateLimiter
+	userID     string
+}
+
+//UserClient signs with one user's own api keys, as returned by CreateAPIKeys, and calls the user scoped endpoints.
+//Those endpoints act on the key's owner, so unlike the Client methods they take no userID.
+type UserClient struct {
+	client *Client
+}
+
+//Config for the client to work
+type Config struct {
+	Endpoint          string
+	WebsocketEndpoint string
+	Environment       string
+	MasterAPIKey      string
+	MasterSecretKey   string
+	//DebugMessages logs every request and response at debug level to stderr when Logger is nil
+	DebugMessages bool
+	//HTTPClient is used to send requests, a plain http.Client is used when nil
+	HTTPClient *http.Client
+	//Middleware wraps the HTTPClient transport, the first entry sees each request first
+	Middleware []Middleware
+	//NonceSource hands out request nonces, a ClockNonceSource is used when nil
+	NonceSource NonceSource
+	//Retry retries failed GET requests, nothing is retried when nil
+	Retry *RetryPolicy
+	//RateLimits caps requests per endpoint category, DefaultRateLimits is used when nil
+	RateLimits map[EndpointCategory]RateLimit
+	//DisableRateLimit sends every request immediately and leaves limiting to shrimpy
+	DisableRateLimit bool
+	//UsageTracker counts the credits each successful call costs, nothing is counted when nil
+	UsageTracker *UsageTracker
+	//Logger receives requests and responses at debug, retries at warn and failed calls at error, with api keys,
+	//signatures and secrets redacted. Its handler's level picks how much is logged, nothing is logged when nil.
+	Logger *slog.Logger
+	//Hooks are called around every try of every api call, in order
+	Hooks []Hooks
+	//Tracer starts a span for every api call, such as shrimpyotel.NewTracer for OpenTelemetry. Nothing is traced when nil
+	Tracer Tracer
+}
+
+//SupportedExchange is an exchange shrimpy supports and its fee range
+type SupportedExchange struct {
+	Exchange     string  `json:"exchange"`
+	BestCaseFee  Decimal `json:"bestCaseFee"`
+	WorstCaseFee Decimal `json:"worstCaseFee"`
+	Icon         string  `json:"icon"`
+}
+
+//SupportedExchanges gets all exchanges that shrimpy suppports
+type SupportedExchanges []SupportedExchange
+
+//Asset is a coin object for an exchange
+type Asset struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Symbol        string `json:"symbol"`
+	TradingSymbol string `json:"tradingSymbol"`
+}
+
+//Assets are coin objects for an exchange
+type Assets []Asset
+
+//Find returns the asset with this symbol, ignoring case
+func (assets Assets) Find(symbol string) (Asset, bool) {
+	for _, asset := range assets {
+		if strings.EqualFold(asset.Symbol, symbol) {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+//Pair is a coin pair for an exchange
+type Pair struct {
+	BaseTradingSymbol  string `json:"baseTradingSymbol"`
+	QuoteTradingSymbol string `json:"quoteTradingSymbol"`
+}
+
+//String formats the pair the way the websocket expects it, such as "btc-usdt"
+func (pair Pair) String() string {
+	return strings.ToLower(pair.BaseTradingSymbol + "-" + pair.QuoteTradingSymbol)
+}
+
+//Pairs are coin pairs for an exchange
+type Pairs []Pair
+
+//Ticker is the latest price of one asset on an exchange
+type Ticker struct {
+	Name                string    `json:"name"`
+	Symbol              string    `json:"symbol"`
+	PriceUsd            Decimal   `json:"priceUsd"`
+	PriceBtc            Decimal   `json:"priceBtc"`
+	PercentChange24HUsd Decimal   `json:"percentChange24hUsd"`
+	LastUpdated         time.Time `json:"lastUpdated"`
+}
+
+//Tickers are tickers for an exchange
+type Tickers []Ticker
+
+//Find returns the ticker for this symbol, ignoring case
+func (tickers Tickers) Find(symbol string) (Ticker, bool) {
+	for _, ticker := range tickers {
+		if strings.EqualFold(ticker.Symbol, symbol) {
+			return ticker, true
+		}
+	}
+	return Ticker{}, false
+}
+
+//OrderBookLevel is the total quantity resting at one price
+type OrderBookLevel struct {
+	Price    Decimal `json:"price"`
+	Quantity Decimal `json:"quantity"`
+}
+
+//Value returns Price multiplied by Quantity, the quote amount resting at this level
+func (level OrderBookLevel) Value() Decimal {
+	return level.Price.Mul(level.Quantity)
+}
+
+//OrderBook holds the asks and bids of one book, each side best price first
+type OrderBook struct {
+	Asks []OrderBookLevel `json:"asks"`
+	Bids []OrderBookLevel `json:"bids"`
+}
+
+//BestBid returns the highest bid, false when there are no bids
+func (book OrderBook) BestBid() (OrderBookLevel, bool) {
+	if len(book.Bids) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return book.Bids[0], true
+}
+
+//BestAsk returns the lowest ask, false when there are no asks
+func (book OrderBook) BestAsk() (OrderBookLevel, bool) {
+	if len(book.Asks) == 0 {
+		return OrderBookLevel{}, false
+	}
+	return book.Asks[0], true
+}
+
+//Spread returns the best ask minus the best bid, false when either side is empty
+func (book OrderBook) Spread() (Decimal, bool) {
+	bid, ok := book.BestBid()
+	if !ok {
+		return Decimal{}, false
+	}
+	ask, ok := book.BestAsk()
+	if !ok {
+		return Decimal{}, false
+	}
+	return ask.Price.Sub(bid.Price), true
+}
+
+//ExchangeOrderBook is the order book for a pair on one exchange
+type ExchangeOrderBook struct {
+	Exchange  string    `json:"exchange"`
+	OrderBook OrderBook `json:"orderBook"`
+}
+
+//MarketOrderBooks holds the order books for one pair across exchanges
+type MarketOrderBooks struct {
+	QuoteSymbol string              `json:"quoteSymbol"`
+	BaseSymbol  string              `json:"baseSymbol"`
+	OrderBooks  []ExchangeOrderBook `json:"orderBooks"`
+}
+
+//ExchangeOrders - list of exchange objects which contains orderbooks
+type ExchangeOrders []MarketOrderBooks
+
+//Candle is one candle stick
+type Candle struct {
+	Open        Decimal   `json:"open"`
+	High        Decimal   `json:"high"`
+	Low         Decimal   `json:"low"`
+	Close       Decimal   `json:"close"`
+	Volume      Decimal   `json:"volume"`
+	QuoteVolume Decimal   `json:"quoteVolume"`
+	BtcVolume   Decimal   `json:"btcVolume"`
+	UsdVolume   Decimal   `json:"usdVolume"`
+	Time        time.Time `json:"time"`
+}
+
+//Change returns Close minus Open
+func (candle Candle) Change() Decimal {
+	return candle.Close.Sub(candle.Open)
+}
+
+//Range returns High minus Low
+func (candle Candle) Range() Decimal {
+	return candle.High.Sub(candle.Low)
+}
+
+//CandleSticks hold all candle sticks for requested period
+type CandleSticks []Candle
+
+//User is a user associated with this masterAPI key
+type User struct {
+	ExpirationDate time.Time `json:"expirationDate"`
+	ID             string    `json:"id"`
+	IsEnabled      bool      `json:"isEnabled"`
+	Name           string    `json:"name"`
+}
+
+//UsersList defines a list of users associated with this masterAPI key
+type UsersList []User
+
+//SingleUser defines a single user object
+type SingleUser = User
+
+//UserID of new user
+type UserID struct {
+	ID string `json:"id"`
+}
+
+//CreateUserRequest optional field creating new user
+type CreateUserRequest struct {
+	Name string `json:"name"`
+}
+
+//SuccessReturn returns whether the operation was successful
+type SuccessReturn struct {
+	Success bool `json:"success"`
+}
+
+//GetPublicAPIKeys returns all public keys associated with this user
+type GetPublicAPIKeys []string
+
+//WhitelistedIPs returns all whitelisted IPS associated with this account
+type WhitelistedIPs []string
+
+//CreateAPIKeyReturn creates new keys for this user
+type CreateAPIKeyReturn struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
+
+//APIKeyPermissions holds shrimpy permissions for a particular public key
+type APIKeyPermissions struct {
+	Account bool `json:"account"`
+	Trade   bool `json:"trade"`
+}
+
+//LinkedExchangeAccount for storing exchange account data
+type LinkedExchangeAccount struct {
+	ID                int               `json:"id"`
+	Exchange          string            `json:"exchange"`
+	IsRebalancing     bool              `json:"isRebalancing"`
+	ExchangeAPIErrors ExchangeAPIErrors `json:"exchangeApiErrors"`
+}
+
+//LinkedAccounts for storing linked account data
+type LinkedAccounts []LinkedExchangeAccount
+
+//LinkAccountRequest is the data we send to link an account to a user
+type LinkAccountRequest struct {
+	Exchange   string `json:"exchange"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+//LinkAccountResponse is the responding id for the account that was just linked
+type LinkAccountResponse struct {
+	ID int `json:"id"`
+}
+
+//CreateTradeResponse stores the trade id of a posted trade
+type CreateTradeResponse struct {
+	ID string `json:"id"`
+}
+
+//CreateTradeRequest is the body for a create trade request. Leave MaxSpreadPercent and MaxSlippagePercent nil to use shrimpy's defaults.
+type CreateTradeRequest struct {
+	FromSymbol         string   `json:"fromSymbol"`
+	ToSymbol           string   `json:"toSymbol"`
+	Amount             Decimal  `json:"amount"`
+	SmartRouting       bool     `json:"smartRouting,omitempty"`
+	MaxSpreadPercent   *Decimal `json:"maxSpreadPercent,omitempty"`
+	MaxSlippagePercent *Decimal `json:"maxSlippagePercent,omitempty"`
+}
+
+//Validate returns an error wrapping ErrInvalidValue when shrimpy would reject the trade
+func (trade CreateTradeRequest) Validate() error {
+	if err := validatePair("fromSymbol", trade.FromSymbol, "toSymbol", trade.ToSymbol); err != nil {
+		return err
+	}
+	if err := validatePositive("amount", trade.Amount); err != nil {
+		return err
+	}
+	if trade.MaxSpreadPercent != nil {
+		if err := validatePercent("maxSpreadPercent", *trade.MaxSpreadPercent); err != nil {
+			return err
+		}
+	}
+	if trade.MaxSlippagePercent != nil {
+		if err := validatePercent("maxSlippagePercent", *trade.MaxSlippagePercent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Trade is a trade placed through shrimpy and how far it has got
+type Trade struct {
+	ID                   string            `json:"id"`
+	FromSymbol           string            `json:"fromSymbol"`
+	ToSymbol             string            `json:"toSymbol"`
+	Amount               Decimal           `json:"amount"`
+	Status               TradeState        `json:"status"`
+	Success              bool              `json:"success"`
+	ErrorCode            int               `json:"errorCode"`
+	ErrorMessage         string            `json:"errorMessage"`
+	ExchangeAPIErrors    ExchangeAPIErrors `json:"exchangeApiErrors"`
+	SmartRouting         bool              `json:"smartRouting"`
+	MaxSpreadPercent     Decimal           `json:"maxSpreadPercent"`
+	MaxSlippagePercent   Decimal           `json:"maxSlippagePercent"`
+	TriggeredMaxSpread   bool              `json:"triggeredMaxSpread"`
+	TriggeredMaxSlippage bool              `json:"triggeredMaxSlippage"`
+}
+
+//BalanceChange is how much of one asset a trade or order added or removed
+type BalanceChange struct {
+	Symbol      string  `json:"symbol"`
+	NativeValue Decimal `json:"nativeValue"`
+	BtcValue    Decimal `json:"btcValue"`
+	UsdValue    Decimal `json:"usdValue"`
+}
+
+//Fill is one execution on the exchange that makes up a trade
+type Fill struct {
+	BaseAmount  Decimal `json:"baseAmount"`
+	BaseSymbol  string  `json:"baseSymbol"`
+	BtcValue    Decimal `json:"btcValue"`
+	Price       Decimal `json:"price"`
+	QuoteAmount Decimal `json:"quoteAmount"`
+	QuoteSymbol string  `json:"quoteSymbol"`
+	Side        Side    `json:"side"`
+	UsdValue    Decimal `json:"usdValue"`
+}
+
+//TradeStatus holds info from requesting status of a particular trade
+type TradeStatus struct {
+	Trade   Trade           `json:"trade"`
+	Changes []BalanceChange `json:"changes"`
+	Fills   []Fill          `json:"fills"`
+}
+
+//AveragePrice returns the quote amount paid per base unit across all fills, to at most 18 decimal places.
+//It is false when nothing has filled yet.
+func (status TradeStatus) AveragePrice() (Decimal, bool) {
+	var base, quote Decimal
+	for _, fill := range status.Fills {
+		base = base.Add(fill.BaseAmount)
+		quote = quote.Add(fill.QuoteAmount)
+	}
+
+	if base.IsZero() {
+		return Decimal{}, false
+	}
+	return quote.Quo(base, 18), true
+}
+
+//ActiveTrades holds an array of active trades associated with a useraccount / exchange
+type ActiveTrades []Trade
+
+//BalanceHistoryEntry is the total value of an exchange account at one point in time
+type BalanceHistoryEntry struct {
+	Date     time.Time `json:"date"`
+	UsdValue Decimal   `json:"usdValue"`
+	BtcValue Decimal   `json:"btcValue"`
+}
+
+//TotalBalanceHistory holds aggregate balance data
+type TotalBalanceHistory []BalanceHistoryEntry
+
+//LimitOrderRequest holds data for a limit order request
+type LimitOrderRequest struct {
+	BaseSymbol  string      `json:"baseSymbol"`
+	QuoteSymbol string      `json:"quoteSymbol"`
+	Quantity    Decimal     `json:"quantity"`
+	Price       Decimal     `json:"price"`
+	Side        Side        `json:"side"`
+	TimeInForce TimeInForce `json:"timeInForce"`
+}
+
+//Validate returns an error wrapping ErrInvalidValue when shrimpy would reject the order
+func (order LimitOrderRequest) Validate() error {
+	if err := validatePair("baseSymbol", order.BaseSymbol, "quoteSymbol", order.QuoteSymbol); err != nil {
+		return err
+	}
+	if err := validatePositive("quantity", order.Quantity); err != nil {
+		return err
+	}
+	if err := validatePositive("price", order.Price); err != nil {
+		return err
+	}
+	if err := order.Side.Validate(); err != nil {
+		return err
+	}
+	return order.TimeInForce.Validate()
+}
+
+//LimitOrderReturn returns ID of order
+type LimitOrderReturn struct {
+	ID string `json:"id"`
+}
+
+//LimitOrder is a limit order placed through shrimpy and how far it has got
+type LimitOrder struct {
+	ID                string            `json:"id"`
+	BaseSymbol        string            `json:"baseSymbol"`
+	QuoteSymbol       string            `json:"quoteSymbol"`
+	Amount            Decimal           `json:"amount"`
+	Price             Decimal           `json:"price"`
+	Side              Side              `json:"side"`
+	TimeInForce       TimeInForce       `json:"timeInForce"`
+	Status            OrderStatus       `json:"status"`
+	CancelRequested   bool              `json:"cancelRequested"`
+	Success           bool              `json:"success"`
+	ErrorCode         int               `json:"errorCode"`
+	ErrorMessage      string            `json:"errorMessage"`
+	ExchangeAPIErrors ExchangeAPIErrors `json:"exchangeApiErrors"`
+}
+
+//LimitOrderStatusReturn holds a particular executed orders information
+type LimitOrderStatusReturn struct {
+	Order   LimitOrder      `json:"order"`
+	Changes []BalanceChange `json:"changes"`
+}
+
+//OpenActiveOrders holds all not in 'completed' state orders
+type OpenActiveOrders []LimitOrder
+
+//Balance is the amount held of one asset and what it is worth
+type Balance struct {
+	Symbol      string  `json:"symbol"`
+	NativeValue Decimal `json:"nativeValue"`
+	BtcValue    Decimal `json:"btcValue"`
+	UsdValue    Decimal `json:"usdValue"`
+}
+
+//ExchangeBalances keeps all balances of each coin
+type ExchangeBalances struct {
+	RetrievedAt time.Time `json:"retrievedAt"`
+	Balances    []Balance `json:"balances"`
+}
+
+//Find returns the balance of this symbol, ignoring case
+func (balances ExchangeBalances) Find(symbol string) (Balance, bool) {
+	for _, balance := range balances.Balances {
+		if strings.EqualFold(balance.Symbol, symbol) {
+			return balance, true
+		}
+	}
+	return Balance{}, false
+}
+
+//UsdValue returns the value of every balance added up in USD
+func (balances ExchangeBalances) UsdValue() Decimal {
+	var total Decimal
+	for _, balance := range balances.Balances {
+		total = total.Add(balance.UsdValue)
+	}
+	return total
+}
+
+//BtcValue returns the value of every balance added up in BTC
+func (balances ExchangeBalances) BtcValue() Decimal {
+	var total Decimal
+	for _, balance := range balances.Balances {
+		total = total.Add(balance.BtcValue)
+	}
+	return total
+}
+
+//Allocation is the share of a strategy held in one asset, percent is out of 100
+type Allocation struct {
+	Symbol  string  `json:"symbol"`
+	Percent Decimal `json:"percent"`
+}
+
+//Strategy is the portfolio an exchange account is rebalanced towards
+type Strategy struct {
+	IsDynamic   bool         `json:"isDynamic"`
+	Allocations []Allocation `json:"allocations"`
+}
+
+//RebalancePeriod is how often in hours an exchange account is automatically rebalanced
+type RebalancePeriod struct {
+	RebalancePeriod int `json:"rebalancePeriod"`
+}
+
+//WebsocketToken is the short lived token used to open a websocket connection
+type WebsocketToken struct {
+	Token string `json:"token"`
+}
+
+//HistoricalInstrument is an instrument historical data is held for and the time ranges covered
+type HistoricalInstrument struct {
+	Exchange           string    `json:"exchange"`
+	BaseTradingSymbol  string    `json:"baseTradingSymbol"`
+	QuoteTradingSymbol string    `json:"quoteTradingSymbol"`
+	OrderBookStartTime time.Time `json:"orderBookStartTime"`
+	OrderBookEndTime   time.Time `json:"orderBookEndTime"`
+	TradeStartTime     time.Time `json:"tradeStartTime"`
+	TradeEndTime       time.Time `json:"tradeEndTime"`
+}
+
+//HistoricalInstruments lists the instruments historical data is held for and the time ranges covered
+type HistoricalInstruments []HistoricalInstrument
+
+//HistoricalTrade is one trade from the historical trades endpoint
+type HistoricalTrade struct {
+	Time      time.Time `json:"time"`
+	Size      Decimal   `json:"size"`
+	Price     Decimal   `json:"price"`
+	TakerSide string    `json:"takerSide"`
+}
+
+//HistoricalTrades holds trades from the historical trades endpoint
+type HistoricalTrades []HistoricalTrade
+
+//HistoricalOrderBookLevel is one price level in a historical order book snapshot
+type HistoricalOrderBookLevel struct {
+	Price Decimal `json:"price"`
+	Size  Decimal `json:"size"`
+}
+
+//HistoricalOrderBook is one order book snapshot from the historical order books endpoint
+type HistoricalOrderBook struct {
+	Time time.Time                  `json:"time"`
+	Asks []HistoricalOrderBookLevel `json:"asks"`
+	Bids []HistoricalOrderBookLevel `json:"bids"`
+}
+
+//HistoricalOrderBooks holds order book snapshots from the historical order books endpoint
+type HistoricalOrderBooks []HistoricalOrderBook
+
+//HistoricalCount is how many historical data points exist for a query
+type HistoricalCount struct {
+	Count int `json:"count"`
+}
+
+//BacktestAsset is an asset that can be backtested on an exchange and the time range covered
+type BacktestAsset struct {
+	Token     string    `json:"token"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+//BacktestAssets lists the assets that can be backtested on an exchange and the time range covered for each
+type BacktestAssets []BacktestAsset
+
+//BacktestRequest describes a simulated rebalancing strategy, Fee is a percentage and InitialValue is in USD.
+//Fee, InitialValue and the allocation percents are plain numbers rather than Decimals because shrimpy expects json numbers in this body.
+type BacktestRequest struct {
+	Exchange        string               `json:"exchange"`
+	RebalancePeriod int                  `json:"rebalancePeriod"`
+	Fee             float64              `json:"fee"`
+	StartTime       time.Time            `json:"startTime"`
+	EndTime         time.Time            `json:"endTime"`
+	InitialValue    float64              `json:"initialValue"`
+	Allocations     []BacktestAllocation `json:"allocations"`
+}
+
+//BacktestAllocation is one asset's target share in a backtest, Percent is sent as a json number
+type BacktestAllocation struct {
+	Symbol  string  `json:"symbol"`
+	Percent float64 `json:"percent"`
+}
+
+//BacktestValue is the simulated portfolio value at one point in time
+type BacktestValue struct {
+	Time  time.Time `json:"time"`
+	Value Decimal   `json:"value"`
+}
+
+//BacktestSeries is the simulated portfolio value over time
+type BacktestSeries struct {
+	Usd []BacktestValue `json:"usd"`
+}
+
+//Final returns the last value in the series, false when it is empty
+func (series BacktestSeries) Final() (BacktestValue, bool) {
+	if len(series.Usd) == 0 {
+		return BacktestValue{}, false
+	}
+	return series.Usd[len(series.Usd)-1], true
+}
+
+//BacktestResult holds the portfolio value over time when rebalancing and when simply holding the starting allocation
+type BacktestResult struct {
+	RebalanceData BacktestSeries `json:"rebalanceData"`
+	HoldingData   BacktestSeries `json:"holdingData"`
+}
+
+//AssetPercentage is one asset's share of a whole, as a percentage
+type AssetPercentage struct {
+	Symbol     string  `json:"symbol"`
+	Percentage Decimal `json:"percentage"`
+}
+
+//AssetDominance holds each asset's share of the total market capitalisation, as a percentage
+type AssetDominance []AssetPercentage
+
+//AssetPopularity holds the share of shrimpy portfolios that allocate to each asset, as a percentage
+type AssetPopularity []AssetPercentage
+
+//APIStatus reports whether the shrimpy api is up
+type APIStatus struct {
+	Status string `json:"status"`
+}
+
+//Credits is the number of credits remaining on the master api key
+type Credits struct {
+	Credits int64 `json:"credits"`
+}
+
+//DailyUsage is the credits shrimpy charged on one day
+type DailyUsage struct {
+	Date    time.Time `json:"date"`
+	Credits int64     `json:"credits"`
+}
+
+//Usage holds the credits shrimpy has charged per day
+type Usage []DailyUsage
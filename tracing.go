@@ -0,0 +1,111 @@
+package shrimpygo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Tracer starts a span for every api call. The shrimpyotel package implements it with OpenTelemetry,
+//keeping the otel modules out of programs that do not trace.
+type Tracer interface {
+	//StartCall starts the span covering one call and all of its tries. The returned context is the one the call runs in.
+	StartCall(ctx context.Context, call CallInfo) (context.Context, CallSpan)
+}
+
+//CallSpan is the span covering one api call
+type CallSpan interface {
+	//Inject writes the span's trace context into the headers of each try so the call joins the caller's trace
+	Inject(header http.Header)
+	//Try records how one try went, it is called after every try with the attempt number, status code and error
+	Try(info ResponseInfo)
+	//End finishes the span, err is the error the call returned to the caller
+	End(err error)
+}
+
+//CallInfo describes an api call for a Tracer. It holds nothing that identifies a user or account.
+type CallInfo struct {
+	Method string
+	//Route is the request path with user, account, trade, order and key ids replaced by placeholders,
+	//such as /v1/users/{userID}/accounts/{accountID}/trades
+	Route    string
+	Category EndpointCategory
+	//Exchange is the exchange the call is about, empty when the path and query do not name one
+	Exchange string
+	//UserIDHash is a short sha256 of the user the call is made for, empty when it is not made for a user
+	UserIDHash string
+}
+
+//routeIDs are the path segments followed by an id, replaced in routes so they do not carry user data
+var routeIDs = map[string]string{
+	"users":    "{userID}",
+	"accounts": "{accountID}",
+	"trades":   "{tradeID}",
+	"orders":   "{orderID}",
+	"keys":     "{publicKey}",
+}
+
+//startCall starts the call's span, nil when Config.Tracer is not set
+func (client *Client) startCall(ctx context.Context, method string, param string, requestPath string) (context.Context, CallSpan) {
+	if client.Config.Tracer == nil {
+		return ctx, nil
+	}
+
+	userID := pathUserID(requestPath)
+	if userID == "" {
+		userID = client.userID
+	}
+
+	call := CallInfo{
+		Method:   method,
+		Route:    callRoute(requestPath),
+		Category: categorize(requestPath),
+		Exchange: callExchange(requestPath, param),
+	}
+	if userID != "" {
+		call.UserIDHash = hashUserID(userID)
+	}
+
+	return client.Config.Tracer.StartCall(ctx, call)
+}
+
+//callRoute returns the request path with user, account, trade, order and key ids replaced by placeholders
+func callRoute(requestPath string) string {
+	segments := strings.Split(requestPath, "/")
+	for i := 1; i < len(segments); i++ {
+		if placeholder, ok := routeIDs[segments[i-1]]; ok && segments[i] != "" {
+			segments[i] = placeholder
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+//callExchange returns the exchange a call is about, from the path or the exchange query parameter
+func callExchange(requestPath string, param string) string {
+	segments := strings.Split(requestPath, "/")
+	for i := 1; i < len(segments); i++ {
+		switch segments[i-1] {
+		case "exchanges":
+			return segments[i]
+		case "backtest":
+			if segments[i] != "run" {
+				return segments[i]
+			}
+		}
+	}
+
+	query, err := url.ParseQuery(strings.TrimPrefix(param, "?"))
+	if err != nil {
+		return ""
+	}
+	return query.Get("exchange")
+}
+
+//hashUserID returns a short sha256 of a user id, enough to group a user's calls without putting the id in a trace
+func hashUserID(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,38 @@
+package shrimpygo
+
+import (
+	"net/http"
+)
+
+//Middleware wraps the RoundTripper used to send every request, e.g. for instrumentation or extra headers
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+//RoundTripperFunc lets an ordinary function be used as a http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+//RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+//newHTTPClient builds the http client used for the lifetime of a Client.
+//The caller's http.Client is copied so wrapping its transport does not leak back into it.
+//Middleware is applied so that the first entry sees the request first.
+func newHTTPClient(config Config) *http.Client {
+	var httpClient http.Client
+	if config.HTTPClient != nil {
+		httpClient = *config.HTTPClient
+	}
+
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(config.Middleware) - 1; i >= 0; i-- {
+		transport = config.Middleware[i](transport)
+	}
+
+	httpClient.Transport = transport
+	return &httpClient
+}
@@ -0,0 +1,148 @@
+package shrimpygo
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+//DefaultUsageCosts charges one credit for every call in every category, replace the figures with the pricing on your shrimpy plan
+func DefaultUsageCosts() map[EndpointCategory]int64 {
+	return map[EndpointCategory]int64{
+		CategoryPublic:     1,
+		CategoryMarket:     1,
+		CategoryUser:       1,
+		CategoryTrading:    1,
+		CategoryHistorical: 1,
+	}
+}
+
+//UsageRecord is the usage attributed to one user in one endpoint category.
+//Calls under /v1/users/<userID> belong to that user and calls made through a UserClient to its userID.
+//Everything else, such as market data on the master client, has an empty UserID.
+type UsageRecord struct {
+	UserID   string
+	Category EndpointCategory
+	Calls    int64
+	Credits  int64
+}
+
+//UsageTracker counts the calls a client makes and the credits they cost, per user and endpoint category.
+//It is safe to share between clients and goroutines. Only requests shrimpy accepted with a 2xx are counted.
+type UsageTracker struct {
+	costs   map[EndpointCategory]int64
+	mu      sync.Mutex
+	records map[usageKey]*UsageRecord
+}
+
+type usageKey struct {
+	userID   string
+	category EndpointCategory
+}
+
+//NewUsageTracker returns a tracker charging costs per call in each category, DefaultUsageCosts is used when nil.
+//Categories missing from costs are counted but cost nothing.
+func NewUsageTracker(costs map[EndpointCategory]int64) *UsageTracker {
+	if costs == nil {
+		costs = DefaultUsageCosts()
+	}
+
+	copied := make(map[EndpointCategory]int64, len(costs))
+	for category, cost := range costs {
+		copied[category] = cost
+	}
+
+	return &UsageTracker{costs: copied, records: make(map[usageKey]*UsageRecord)}
+}
+
+//Record counts one call by userID in category
+func (tracker *UsageTracker) Record(userID string, category EndpointCategory) {
+	if tracker == nil {
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	key := usageKey{userID: userID, category: category}
+	record := tracker.records[key]
+	if record == nil {
+		record = &UsageRecord{UserID: userID, Category: category}
+		tracker.records[key] = record
+	}
+
+	record.Calls++
+	record.Credits += tracker.costs[category]
+}
+
+//Usage returns every record, sorted by user and then category
+func (tracker *UsageTracker) Usage() []UsageRecord {
+	if tracker == nil {
+		return nil
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.snapshot()
+}
+
+//UserCredits returns the credits attributed to userID across all categories
+func (tracker *UsageTracker) UserCredits(userID string) int64 {
+	if tracker == nil {
+		return 0
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	var credits int64
+	for key, record := range tracker.records {
+		if key.userID == userID {
+			credits += record.Credits
+		}
+	}
+	return credits
+}
+
+//Reset returns every record and starts counting again from zero, handy for billing periods
+func (tracker *UsageTracker) Reset() []UsageRecord {
+	if tracker == nil {
+		return nil
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	records := tracker.snapshot()
+	tracker.records = make(map[usageKey]*UsageRecord)
+	return records
+}
+
+//snapshot copies the records out in a stable order. Callers hold mu
+func (tracker *UsageTracker) snapshot() []UsageRecord {
+	records := make([]UsageRecord, 0, len(tracker.records))
+	for _, record := range tracker.records {
+		records = append(records, *record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].UserID != records[j].UserID {
+			return records[i].UserID < records[j].UserID
+		}
+		return records[i].Category < records[j].Category
+	})
+
+	return records
+}
+
+//pathUserID returns the user a request path belongs to, empty when it is not under /v1/users/<userID>
+func pathUserID(requestPath string) string {
+	rest := strings.TrimPrefix(requestPath, "/v1/users/")
+	if rest == requestPath {
+		return ""
+	}
+
+	userID, _, _ := strings.Cut(rest, "/")
+	return userID
+}
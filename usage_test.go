@@ -0,0 +1,84 @@
+package shrimpygo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUsageTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tracker := NewUsageTracker(map[EndpointCategory]int64{CategoryUser: 5, CategoryPublic: 1})
+	client := NewClient(Config{
+		Endpoint:         server.URL,
+		MasterAPIKey:     "key",
+		MasterSecretKey:  "c2VjcmV0",
+		DisableRateLimit: true,
+		UsageTracker:     tracker,
+	})
+	user := client.NewUserClient("user-b", "user-key", "c2VjcmV0")
+
+	ctx := context.Background()
+	if _, err := client.GetSupportedExchanges(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListAccounts(ctx, "user-a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := user.ListAccounts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := user.ListAccounts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := user.GetActiveTrades(ctx, "42"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []UsageRecord{
+		{UserID: "", Category: CategoryPublic, Calls: 1, Credits: 1},
+		{UserID: "user-a", Category: CategoryUser, Calls: 1, Credits: 5},
+		{UserID: "user-b", Category: CategoryTrading, Calls: 1, Credits: 0},
+		{UserID: "user-b", Category: CategoryUser, Calls: 2, Credits: 10},
+	}
+	got := tracker.Usage()
+	if len(got) != len(want) {
+		t.Fatalf("usage = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if credits := tracker.UserCredits("user-b"); credits != 10 {
+		t.Errorf("UserCredits(user-b) = %d, want 10", credits)
+	}
+
+	if reset := tracker.Reset(); len(reset) != len(want) {
+		t.Errorf("Reset returned %d records, want %d", len(reset), len(want))
+	}
+	if usage := tracker.Usage(); len(usage) != 0 {
+		t.Errorf("usage after Reset = %+v", usage)
+	}
+}
+
+func TestUsageTrackerNil(t *testing.T) {
+	var tracker *UsageTracker
+	tracker.Record("user", CategoryUser)
+
+	if usage := tracker.Usage(); usage != nil {
+		t.Errorf("Usage = %+v, want nil", usage)
+	}
+	if credits := tracker.UserCredits("user"); credits != 0 {
+		t.Errorf("UserCredits = %d, want 0", credits)
+	}
+	if reset := tracker.Reset(); reset != nil {
+		t.Errorf("Reset = %+v, want nil", reset)
+	}
+}
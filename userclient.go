@@ -0,0 +1,160 @@
+package shrimpygo
+
+import "context"
+
+//NewUserClient returns a client that authenticates with a user's publicKey and privateKey instead of the master keys.
+//It shares this client's endpoint, http client, middleware, retry policy, nonce source and usage tracker, and gets rate
+//limits of its own. userID is only used to attribute usage to the user and may be left empty.
+func (client *Client) NewUserClient(userID string, publicKey string, privateKey string) *UserClient {
+	config := client.Config
+	config.MasterAPIKey = publicKey
+	config.MasterSecretKey = privateKey
+
+	user := NewClient(config)
+	user.userID = userID
+
+	return &UserClient{client: user}
+}
+
+/*
+
+	START ACCOUNTS FUNCTIONS
+
+*/
+
+//ListAccounts will return an array of exchange accounts linked with this user
+func (user *UserClient) ListAccounts(ctx context.Context) (LinkedAccounts, error) {
+	r := new(LinkedAccounts)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts", "", r)
+	return *r, err
+}
+
+//GetAccount will return a singular linked exchange object
+func (user *UserClient) GetAccount(ctx context.Context, exchangeAccountID string) (LinkedExchangeAccount, error) {
+	r := new(LinkedExchangeAccount)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeAccountID, "", r)
+	return *r, err
+}
+
+/*
+
+	END ACCOUNTS FUNCTIONS
+	START TRADING FUNCTIONS
+
+*/
+
+//CreateTrade will post a trade to this exchange
+func (user *UserClient) CreateTrade(ctx context.Context, exchangeID string, fromSymbol string, toSymbol string, amount Decimal, smartRouting bool, maxSpreadPercent Decimal, maxSlippagePercent Decimal) (CreateTradeResponse, error) {
+	return user.SubmitTrade(ctx, exchangeID, newCreateTradeRequest(fromSymbol, toSymbol, amount, smartRouting, maxSpreadPercent, maxSlippagePercent))
+}
+
+//SubmitTrade validates the trade and posts it to this exchange
+func (user *UserClient) SubmitTrade(ctx context.Context, exchangeID string, trade CreateTradeRequest) (CreateTradeResponse, error) {
+	return user.client.submitTrade(ctx, "/v1/accounts/"+exchangeID, trade)
+}
+
+//GetTradeStatus will return the details of a particular trade
+func (user *UserClient) GetTradeStatus(ctx context.Context, exchangeID string, tradeID string) (TradeStatus, error) {
+	r := new(TradeStatus)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/trades/"+tradeID, "", r)
+	return *r, err
+}
+
+//GetActiveTrades will return all trades who status is not 'completed'
+func (user *UserClient) GetActiveTrades(ctx context.Context, exchangeID string) (ActiveTrades, error) {
+	r := new(ActiveTrades)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/trades", "", r)
+	return *r, err
+}
+
+/*
+
+	END TRADING FUNCTIONS
+	START BALANCE FUNCTIONS
+
+*/
+
+//GetBalance will return the balances on all held assets on that exchange
+func (user *UserClient) GetBalance(ctx context.Context, exchangeID string) (ExchangeBalances, error) {
+	r := new(ExchangeBalances)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/balance", "", r)
+	return *r, err
+}
+
+//GetTotalBalanceHistory gets an aggregate balance history for an exchange account
+func (user *UserClient) GetTotalBalanceHistory(ctx context.Context, exchangeID string) (TotalBalanceHistory, error) {
+	r := new(TotalBalanceHistory)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/total_balance_history", "", r)
+	return *r, err
+}
+
+/*
+
+	END BALANCE FUNCTIONS
+	START LIMIT ORDER FUNCTIONS
+
+*/
+
+//PlaceLimitOrder posts a limit order to the exchange
+func (user *UserClient) PlaceLimitOrder(ctx context.Context, exchangeID string, baseSymbol string, quoteSymbol string, quantity Decimal, side Side, timeInForce TimeInForce, price Decimal) (LimitOrderReturn, error) {
+	order := LimitOrderRequest{
+		BaseSymbol:  baseSymbol,
+		QuoteSymbol: quoteSymbol,
+		Quantity:    quantity,
+		Price:       price,
+		Side:        side,
+		TimeInForce: timeInForce,
+	}
+
+	return user.SubmitLimitOrder(ctx, exchangeID, order)
+}
+
+//SubmitLimitOrder validates the order and posts it to the exchange
+func (user *UserClient) SubmitLimitOrder(ctx context.Context, exchangeID string, order LimitOrderRequest) (LimitOrderReturn, error) {
+	return user.client.submitLimitOrder(ctx, "/v1/accounts/"+exchangeID, order)
+}
+
+//GetLimitOrderStatus gets the status of a particular order
+func (user *UserClient) GetLimitOrderStatus(ctx context.Context, exchangeID string, orderID string) (LimitOrderStatusReturn, error) {
+	r := new(LimitOrderStatusReturn)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/orders/"+orderID, "", r)
+	return *r, err
+}
+
+//ListOpenOrders gets all orders not in a 'complete' state for a particular exchange
+func (user *UserClient) ListOpenOrders(ctx context.Context, exchangeID string) (OpenActiveOrders, error) {
+	r := new(OpenActiveOrders)
+	params := ""
+
+	err := user.client.request(ctx, GET, params, "/v1/accounts/"+exchangeID+"/orders", "", r)
+	return *r, err
+}
+
+//CancelLimitOrder cancels a particular order on that exchange
+func (user *UserClient) CancelLimitOrder(ctx context.Context, exchangeID string, orderID string) (SuccessReturn, error) {
+	r := new(SuccessReturn)
+	params := ""
+
+	err := user.client.request(ctx, DELETE, params, "/v1/accounts/"+exchangeID+"/orders/"+orderID, "", r)
+	return *r, err
+}
+
+/*
+
+	END LIMIT ORDER FUNCTIONS
+
+*/
@@ -0,0 +1,40 @@
+package shrimpygo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//ErrInvalidValue is wrapped by every error for a value shrimpy would not accept, caught before the request is signed
+var ErrInvalidValue = errors.New("shrimpygo: invalid value")
+
+//validatePair checks both symbols are set and are not the same asset
+func validatePair(firstName string, first string, secondName string, second string) error {
+	if first == "" {
+		return fmt.Errorf("%w: %s is required", ErrInvalidValue, firstName)
+	}
+	if second == "" {
+		return fmt.Errorf("%w: %s is required", ErrInvalidValue, secondName)
+	}
+	if strings.EqualFold(first, second) {
+		return fmt.Errorf("%w: %s and %s are both %q", ErrInvalidValue, firstName, secondName, first)
+	}
+	return nil
+}
+
+//validatePositive checks an amount is above zero
+func validatePositive(name string, value Decimal) error {
+	if value.Sign() <= 0 {
+		return fmt.Errorf("%w: %s must be above zero, got %s", ErrInvalidValue, name, value)
+	}
+	return nil
+}
+
+//validatePercent checks a percentage is above zero and at most 100
+func validatePercent(name string, value Decimal) error {
+	if value.Sign() <= 0 || value.Cmp(NewDecimal(100, 0)) > 0 {
+		return fmt.Errorf("%w: %s must be above 0 and at most 100, got %s", ErrInvalidValue, name, value)
+	}
+	return nil
+}
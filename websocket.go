@@ -0,0 +1,764 @@
+package shrimpygo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	//ChannelBBO streams the best bid and offer for a pair
+	ChannelBBO = "bbo"
+	//ChannelOrderBook streams the order book for a pair as a snapshot followed by updates
+	ChannelOrderBook = "orderbook"
+	//ChannelTrade streams trades for a pair as they happen
+	ChannelTrade = "trade"
+)
+
+//DefaultWebsocketBufferSize is the capacity of each subscription channel when WebsocketClient.BufferSize is unset
+const DefaultWebsocketBufferSize = 256
+
+var (
+	//ErrWebsocketClosed is returned when using a WebsocketClient that is not connected
+	ErrWebsocketClosed = errors.New("shrimpygo: websocket closed")
+	//ErrWebsocketConnected is returned by Connect when the client is already connected or reconnecting
+	ErrWebsocketConnected = errors.New("shrimpygo: websocket already connected")
+	//ErrSlowConsumer is reported on Errors when a subscription channel is full and a message was dropped
+	ErrSlowConsumer = errors.New("shrimpygo: subscription channel full, message dropped")
+)
+
+//WebsocketError is an error message sent by shrimpy over the websocket
+type WebsocketError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *WebsocketError) Error() string {
+	return fmt.Sprintf("shrimpygo: websocket error %d: %s", e.Code, e.Message)
+}
+
+//WebsocketOrderBooks is the content of a bbo or orderbook message.
+//Shrimpy sends it either as a single book or a list of books, both decode into a slice.
+type WebsocketOrderBooks []OrderBook
+
+//UnmarshalJSON accepts both a single book object and an array of books
+func (books *WebsocketOrderBooks) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var book OrderBook
+		if err := json.Unmarshal(trimmed, &book); err != nil {
+			return err
+		}
+		*books = WebsocketOrderBooks{book}
+		return nil
+	}
+
+	var list []OrderBook
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*books = list
+	return nil
+}
+
+//WebsocketTrade is a single streamed trade
+type WebsocketTrade struct {
+	ID        json.Number `json:"id"`
+	Price     Decimal     `json:"price"`
+	Quantity  Decimal     `json:"quantity"`
+	Time      time.Time   `json:"time"`
+	BtcValue  Decimal     `json:"btcValue"`
+	UsdValue  Decimal     `json:"usdValue"`
+	TakerSide string      `json:"takerSide"`
+}
+
+//BBOMessage is one message from the bbo channel
+type BBOMessage struct {
+	Exchange string              `json:"exchange"`
+	Pair     string              `json:"pair"`
+	Channel  string              `json:"channel"`
+	Snapshot bool                `json:"snapshot"`
+	Sequence int64               `json:"sequence"`
+	Content  WebsocketOrderBooks `json:"content"`
+}
+
+//OrderBookMessage is one message from the orderbook channel.
+//The first message after subscribing is a snapshot, the rest are changed levels where a zero quantity removes the level.
+type OrderBookMessage struct {
+	Exchange string              `json:"exchange"`
+	Pair     string              `json:"pair"`
+	Channel  string              `json:"channel"`
+	Snapshot bool                `json:"snapshot"`
+	Sequence int64               `json:"sequence"`
+	Content  WebsocketOrderBooks `json:"content"`
+}
+
+//TradeMessage is one message from the trade channel
+type TradeMessage struct {
+	Exchange string           `json:"exchange"`
+	Pair     string           `json:"pair"`
+	Channel  string           `json:"channel"`
+	Snapshot bool             `json:"snapshot"`
+	Sequence int64            `json:"sequence"`
+	Content  []WebsocketTrade `json:"content"`
+}
+
+//websocketEnvelope holds the fields needed to route an incoming message
+type websocketEnvelope struct {
+	Type     string          `json:"type"`
+	Data     json.RawMessage `json:"data"`
+	Code     int             `json:"code"`
+	Message  string          `json:"message"`
+	Exchange string          `json:"exchange"`
+	Pair     string          `json:"pair"`
+	Channel  string          `json:"channel"`
+}
+
+//websocketRequest is a subscribe, unsubscribe or pong message sent to shrimpy
+type websocketRequest struct {
+	Type     string          `json:"type"`
+	Exchange string          `json:"exchange,omitempty"`
+	Pair     string          `json:"pair,omitempty"`
+	Channel  string          `json:"channel,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+}
+
+type subscriptionKey struct {
+	channel  string
+	exchange string
+	pair     string
+}
+
+func newSubscriptionKey(channel string, exchange string, pair string) subscriptionKey {
+	return subscriptionKey{channel: channel, exchange: strings.ToLower(exchange), pair: strings.ToLower(pair)}
+}
+
+//subscription decodes raw messages onto the subscriber's typed channel
+type subscription struct {
+	deliver func(raw []byte) error
+	close   func()
+}
+
+//WebsocketEventType says what happened to the connection
+type WebsocketEventType string
+
+const (
+	//EventDisconnected is sent when the connection drops or stops answering pings
+	EventDisconnected WebsocketEventType = "disconnected"
+	//EventResync is sent once a dropped connection is back and every subscription has been replayed.
+	//Anything built from the streams before the drop may be stale, order books get a fresh snapshot.
+	EventResync WebsocketEventType = "resync"
+)
+
+//Subscription identifies one stream on the websocket
+type Subscription struct {
+	Channel  string
+	Exchange string
+	Pair     string
+}
+
+//WebsocketEvent reports a drop or a recovery of the websocket connection
+type WebsocketEvent struct {
+	Type WebsocketEventType
+	//Err is why the connection dropped
+	Err error
+	//DisconnectedAt is when the drop was noticed, messages from then until ReconnectedAt were missed
+	DisconnectedAt time.Time
+	//ReconnectedAt is set on EventResync
+	ReconnectedAt time.Time
+	//Subscriptions were replayed on the new connection, set on EventResync
+	Subscriptions []Subscription
+}
+
+//WebsocketClient streams market data from shrimpy's websocket feed.
+//Each subscription gets its own channel, which is closed on Unsubscribe or Close.
+//Dropped connections are reopened with a fresh token and every subscription is replayed,
+//the channels staying open throughout.
+type WebsocketClient struct {
+	//BufferSize is the capacity of each subscription channel, set it before subscribing
+	BufferSize int
+	//PingTimeout is how long to wait for any message or ping before treating the connection as dropped, 1 minute when unset
+	PingTimeout time.Duration
+	//Reconnect controls the wait between reconnect attempts and when to give up, DefaultReconnectPolicy is used when nil
+	Reconnect *ReconnectPolicy
+
+	client *Client
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	subs    map[subscriptionKey]*subscription
+	errs    chan error
+	events  chan WebsocketEvent
+	done    chan struct{}
+	cancel  context.CancelFunc
+
+	//disconnectHooks run on the read goroutine whenever the connection drops
+	disconnectHooks []func()
+}
+
+//ReconnectPolicy controls how a dropped websocket connection is reopened
+type ReconnectPolicy struct {
+	//MaxAttempts is how many reconnects are tried before giving up, zero or less keeps trying until Close
+	MaxAttempts int
+	//InitialBackoff is the wait before the first reconnect
+	InitialBackoff time.Duration
+	//MaxBackoff caps the wait between any two reconnects
+	MaxBackoff time.Duration
+	//Multiplier grows the wait after every reconnect, 2 is used when unset
+	Multiplier float64
+	//Jitter is the fraction of each wait that is randomised, between 0 and 1
+	Jitter float64
+}
+
+//DefaultReconnectPolicy retries forever, backing off from half a second up to 30 seconds
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+//NewWebsocketClient returns a websocket client that authenticates through this client
+func (client *Client) NewWebsocketClient() *WebsocketClient {
+	return &WebsocketClient{
+		client: client,
+		subs:   make(map[subscriptionKey]*subscription),
+		errs:   make(chan error, 16),
+		events: make(chan WebsocketEvent, 16),
+	}
+}
+
+//Connect fetches a websocket token and opens the connection.
+//It returns ErrWebsocketConnected until the previous connection has stopped for good, after Close or when reconnecting gives up.
+func (ws *WebsocketClient) Connect(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	//claim the client before dialing so two Connects cannot both start a run
+	ws.mu.Lock()
+	if ws.done != nil && !isClosed(ws.done) {
+		ws.mu.Unlock()
+		cancel()
+		return ErrWebsocketConnected
+	}
+	ws.done = done
+	ws.cancel = cancel
+	ws.mu.Unlock()
+
+	conn, err := ws.dial(ctx)
+	if err == nil {
+		//a Close while dialing has already cancelled runCtx and will not see this conn, so give up on it here
+		ws.mu.Lock()
+		if runCtx.Err() == nil {
+			ws.conn = conn
+		} else {
+			conn.Close()
+			err = ErrWebsocketClosed
+		}
+		ws.mu.Unlock()
+	}
+	if err != nil {
+		cancel()
+		close(done)
+		return err
+	}
+
+	go ws.run(runCtx, conn, done)
+	return nil
+}
+
+//dial fetches a fresh token and opens a connection with it
+func (ws *WebsocketClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	token, err := ws.client.GetWebsocketToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := ws.client.Config.websocketEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 45 * time.Second,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, endpoint+"/?token="+url.QueryEscape(token.Token), nil)
+	if err != nil {
+		return nil, fmt.Errorf("shrimpygo: websocket dial: %w", err)
+	}
+
+	return conn, nil
+}
+
+//Errors delivers errors sent by shrimpy, connection failures and dropped messages.
+//Errors are discarded once its small buffer fills up, so keep reading it.
+func (ws *WebsocketClient) Errors() <-chan error {
+	return ws.errs
+}
+
+//Events delivers disconnect and resync notifications.
+//Events are discarded once its small buffer fills up, so keep reading it.
+func (ws *WebsocketClient) Events() <-chan WebsocketEvent {
+	return ws.events
+}
+
+//Done is closed once the client has stopped for good, after Close or when reconnecting gives up
+func (ws *WebsocketClient) Done() <-chan struct{} {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.done
+}
+
+//SubscribeBBO subscribes to the best bid and offer for a pair such as "btc-usdt"
+func (ws *WebsocketClient) SubscribeBBO(ctx context.Context, exchange string, pair string) (<-chan BBOMessage, error) {
+	ch := make(chan BBOMessage, ws.bufferSize())
+	err := ws.subscribe(ctx, ChannelBBO, exchange, pair, &subscription{
+		deliver: func(raw []byte) error {
+			var m BBOMessage
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			select {
+			case ch <- m:
+				return nil
+			default:
+				return ErrSlowConsumer
+			}
+		},
+		close: func() { close(ch) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+//SubscribeOrderBook subscribes to the order book for a pair such as "btc-usdt"
+func (ws *WebsocketClient) SubscribeOrderBook(ctx context.Context, exchange string, pair string) (<-chan OrderBookMessage, error) {
+	ch := make(chan OrderBookMessage, ws.bufferSize())
+	err := ws.subscribe(ctx, ChannelOrderBook, exchange, pair, &subscription{
+		deliver: func(raw []byte) error {
+			var m OrderBookMessage
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			select {
+			case ch <- m:
+				return nil
+			default:
+				return ErrSlowConsumer
+			}
+		},
+		close: func() { close(ch) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+//SubscribeTrades subscribes to trades for a pair such as "btc-usdt"
+func (ws *WebsocketClient) SubscribeTrades(ctx context.Context, exchange string, pair string) (<-chan TradeMessage, error) {
+	ch := make(chan TradeMessage, ws.bufferSize())
+	err := ws.subscribe(ctx, ChannelTrade, exchange, pair, &subscription{
+		deliver: func(raw []byte) error {
+			var m TradeMessage
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			select {
+			case ch <- m:
+				return nil
+			default:
+				return ErrSlowConsumer
+			}
+		},
+		close: func() { close(ch) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+//Unsubscribe stops a subscription and closes its channel
+func (ws *WebsocketClient) Unsubscribe(ctx context.Context, channel string, exchange string, pair string) error {
+	key := newSubscriptionKey(channel, exchange, pair)
+
+	ws.mu.Lock()
+	sub, ok := ws.subs[key]
+	delete(ws.subs, key)
+	connected := ws.conn != nil
+	ws.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	sub.close()
+
+	if !connected {
+		return nil
+	}
+
+	return ws.write(ctx, websocketRequest{Type: "unsubscribe", Exchange: key.exchange, Pair: key.pair, Channel: channel})
+}
+
+//Close stops reconnecting, closes the connection and every subscription channel.
+//It returns once the client has stopped, so Connect can be called again straight after.
+func (ws *WebsocketClient) Close() error {
+	ws.mu.Lock()
+	cancel := ws.cancel
+	done := ws.done
+	ws.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	//cancel before looking at conn, a connection made from here on sees the cancelled context and closes itself
+	cancel()
+
+	ws.mu.Lock()
+	conn := ws.conn
+	ws.mu.Unlock()
+
+	var err error
+	if conn != nil {
+		ws.writeMu.Lock()
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		ws.writeMu.Unlock()
+
+		//run may have closed conn first, once the server answered the close message
+		if err = conn.Close(); errors.Is(err, net.ErrClosed) {
+			err = nil
+		}
+	}
+
+	<-done
+	return err
+}
+
+func (ws *WebsocketClient) bufferSize() int {
+	if ws.BufferSize > 0 {
+		return ws.BufferSize
+	}
+	return DefaultWebsocketBufferSize
+}
+
+func (ws *WebsocketClient) pingTimeout() time.Duration {
+	if ws.PingTimeout > 0 {
+		return ws.PingTimeout
+	}
+	return time.Minute
+}
+
+//subscribe registers sub before asking shrimpy for the channel so no message is missed.
+//While reconnecting the subscription is only registered, it is sent with the replay.
+func (ws *WebsocketClient) subscribe(ctx context.Context, channel string, exchange string, pair string, sub *subscription) error {
+	key := newSubscriptionKey(channel, exchange, pair)
+
+	ws.mu.Lock()
+	if ws.done == nil || isClosed(ws.done) {
+		ws.mu.Unlock()
+		return ErrWebsocketClosed
+	}
+	if old, ok := ws.subs[key]; ok {
+		old.close()
+	}
+	ws.subs[key] = sub
+	connected := ws.conn != nil
+	ws.mu.Unlock()
+
+	if !connected {
+		return nil
+	}
+
+	err := ws.write(ctx, websocketRequest{Type: "subscribe", Exchange: key.exchange, Pair: key.pair, Channel: channel})
+	if err != nil {
+		ws.mu.Lock()
+		if ws.subs[key] == sub {
+			delete(ws.subs, key)
+			sub.close()
+		}
+		ws.mu.Unlock()
+	}
+
+	return err
+}
+
+//write sends a json message, gorilla connections allow only one writer at a time
+func (ws *WebsocketClient) write(ctx context.Context, v interface{}) error {
+	ws.mu.Lock()
+	conn := ws.conn
+	ws.mu.Unlock()
+
+	if conn == nil {
+		return ErrWebsocketClosed
+	}
+
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	conn.SetWriteDeadline(deadline)
+
+	return conn.WriteJSON(v)
+}
+
+//run reads from the connection, reconnecting whenever it drops, until Close is called or reconnecting gives up
+func (ws *WebsocketClient) run(ctx context.Context, conn *websocket.Conn, done chan struct{}) {
+	defer ws.shutdown(done)
+
+	for {
+		err := ws.readLoop(conn)
+		conn.Close()
+
+		ws.mu.Lock()
+		ws.conn = nil
+		ws.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		disconnectedAt := time.Now()
+		ws.reportError(fmt.Errorf("shrimpygo: websocket read: %w", err))
+		ws.emit(WebsocketEvent{Type: EventDisconnected, Err: err, DisconnectedAt: disconnectedAt})
+		ws.disconnected()
+
+		conn, err = ws.reconnect(ctx, disconnectedAt)
+		if err != nil {
+			if ctx.Err() == nil {
+				ws.reportError(fmt.Errorf("shrimpygo: websocket reconnect: %w", err))
+			}
+			return
+		}
+	}
+}
+
+//readLoop routes incoming messages until the connection fails or goes quiet for longer than PingTimeout
+func (ws *WebsocketClient) readLoop(conn *websocket.Conn) error {
+	timeout := ws.pingTimeout()
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		ws.writeMu.Lock()
+		defer ws.writeMu.Unlock()
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(10*time.Second))
+	})
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		ws.handle(raw)
+	}
+}
+
+//reconnect dials with a fresh token, backing off between attempts, and replays every subscription
+func (ws *WebsocketClient) reconnect(ctx context.Context, disconnectedAt time.Time) (*websocket.Conn, error) {
+	policy := ws.Reconnect
+	if policy == nil {
+		policy = DefaultReconnectPolicy()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := sleepContext(ctx, policy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+
+		conn, err := ws.dial(ctx)
+		if err == nil {
+			var subs []Subscription
+			subs, err = ws.resubscribe(ctx, conn)
+			if err == nil {
+				ws.emit(WebsocketEvent{Type: EventResync, DisconnectedAt: disconnectedAt, ReconnectedAt: time.Now(), Subscriptions: subs})
+				return conn, nil
+			}
+
+			ws.mu.Lock()
+			ws.conn = nil
+			ws.mu.Unlock()
+			conn.Close()
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+
+		ws.reportError(fmt.Errorf("shrimpygo: websocket reconnect attempt %d: %w", attempt, err))
+	}
+}
+
+//resubscribe makes conn the live connection and sends a subscribe for every registered subscription.
+//Subscriptions made from here on write straight to conn, so none are missed or sent twice.
+func (ws *WebsocketClient) resubscribe(ctx context.Context, conn *websocket.Conn) ([]Subscription, error) {
+	ws.mu.Lock()
+	//Close cancels ctx before it looks for a conn to close, so after that the new conn must not be handed out
+	if ctx.Err() != nil {
+		ws.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	ws.conn = conn
+	keys := make([]subscriptionKey, 0, len(ws.subs))
+	for key := range ws.subs {
+		keys = append(keys, key)
+	}
+	ws.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(keys))
+	for _, key := range keys {
+		err := ws.write(ctx, websocketRequest{Type: "subscribe", Exchange: key.exchange, Pair: key.pair, Channel: key.channel})
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, Subscription{Channel: key.channel, Exchange: key.exchange, Pair: key.pair})
+	}
+
+	return subs, nil
+}
+
+//handle answers pings, reports errors and hands market data to its subscription
+func (ws *WebsocketClient) handle(raw []byte) {
+	var envelope websocketEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		ws.reportError(fmt.Errorf("shrimpygo: websocket message: %w", err))
+		return
+	}
+
+	switch envelope.Type {
+	case "ping":
+		if err := ws.write(context.Background(), websocketRequest{Type: "pong", Data: envelope.Data}); err != nil {
+			ws.reportError(err)
+		}
+		return
+	case "error":
+		ws.reportError(&WebsocketError{Code: envelope.Code, Message: envelope.Message})
+		return
+	}
+
+	if envelope.Channel == "" {
+		return
+	}
+
+	key := newSubscriptionKey(envelope.Channel, envelope.Exchange, envelope.Pair)
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if sub, ok := ws.subs[key]; ok {
+		if err := sub.deliver(raw); err != nil {
+			ws.reportError(fmt.Errorf("%s %s %s: %w", key.channel, key.exchange, key.pair, err))
+		}
+	}
+}
+
+//shutdown closes every subscription once the client has stopped for good
+func (ws *WebsocketClient) shutdown(done chan struct{}) {
+	ws.mu.Lock()
+	ws.conn = nil
+	subs := ws.subs
+	ws.subs = make(map[subscriptionKey]*subscription)
+	ws.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+
+	close(done)
+}
+
+//reportError hands err to Errors without ever blocking the read loop
+func (ws *WebsocketClient) reportError(err error) {
+	select {
+	case ws.errs <- err:
+	default:
+	}
+}
+
+//emit hands event to Events without ever blocking the read loop
+func (ws *WebsocketClient) emit(event WebsocketEvent) {
+	select {
+	case ws.events <- event:
+	default:
+	}
+}
+
+//onDisconnect registers f to run whenever the connection drops
+func (ws *WebsocketClient) onDisconnect(f func()) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.disconnectHooks = append(ws.disconnectHooks, f)
+}
+
+//disconnected runs the disconnect hooks
+func (ws *WebsocketClient) disconnected() {
+	ws.mu.Lock()
+	hooks := ws.disconnectHooks
+	ws.mu.Unlock()
+
+	for _, f := range hooks {
+		f()
+	}
+}
+
+//connected reports whether there is a live connection
+func (ws *WebsocketClient) connected() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.conn != nil
+}
+
+//refresh unsubscribes and subscribes again without closing the subscription's channel, so shrimpy sends a fresh snapshot
+func (ws *WebsocketClient) refresh(ctx context.Context, channel string, exchange string, pair string) error {
+	key := newSubscriptionKey(channel, exchange, pair)
+	if err := ws.write(ctx, websocketRequest{Type: "unsubscribe", Exchange: key.exchange, Pair: key.pair, Channel: channel}); err != nil {
+		return err
+	}
+	return ws.write(ctx, websocketRequest{Type: "subscribe", Exchange: key.exchange, Pair: key.pair, Channel: channel})
+}
+
+//backoff returns how long to wait before the given reconnect, growing the same way as RetryPolicy
+func (policy *ReconnectPolicy) backoff(attempt int) time.Duration {
+	retry := RetryPolicy{
+		InitialBackoff: policy.InitialBackoff,
+		MaxBackoff:     policy.MaxBackoff,
+		Multiplier:     policy.Multiplier,
+		Jitter:         policy.Jitter,
+	}
+	return retry.backoff(attempt)
+}
+
+//isClosed reports whether ch has been closed
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
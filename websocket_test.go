@@ -0,0 +1,188 @@
+package shrimpygo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testOrderBookSnapshot = `{"exchange":"binance","pair":"ltc-btc","channel":"orderbook","snapshot":true,"sequence":0,"content":{"asks":[],"bids":[]}}`
+
+//newTestWebsocket returns a websocket client for a server that answers every subscribe with an order book snapshot.
+//drop is called with the connection number, counting from 1, after each snapshot and closes the connection when it returns true.
+func newTestWebsocket(t *testing.T, drop func(conn int32) bool) (*WebsocketClient, *httptest.Server, *int32) {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	var conns int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/ws/token" {
+			w.Write([]byte(`{"token":"abc"}`))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&conns, 1)
+
+		for {
+			_, raw, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(raw), `"subscribe"`) {
+				conn.WriteMessage(websocket.TextMessage, []byte(testOrderBookSnapshot))
+				if drop(n) {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(Config{
+		Endpoint:          server.URL,
+		WebsocketEndpoint: "ws" + strings.TrimPrefix(server.URL, "http"),
+		DisableRateLimit:  true,
+	})
+	ws := client.NewWebsocketClient()
+	ws.Reconnect = &ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	return ws, server, &conns
+}
+
+func receiveEvent(t *testing.T, ws *WebsocketClient) WebsocketEvent {
+	t.Helper()
+	select {
+	case event := <-ws.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a websocket event")
+		return WebsocketEvent{}
+	}
+}
+
+func receiveBook(t *testing.T, ch <-chan OrderBookMessage) {
+	t.Helper()
+	select {
+	case _, ok := <-ch:
+		if !ok {
+			t.Fatal("order book channel closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an order book message")
+	}
+}
+
+func TestWebsocketReconnect(t *testing.T) {
+	ws, _, conns := newTestWebsocket(t, func(conn int32) bool { return conn == 1 })
+	ctx := context.Background()
+
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	books, err := ws.SubscribeOrderBook(ctx, "binance", "ltc-btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveBook(t, books)
+
+	if event := receiveEvent(t, ws); event.Type != EventDisconnected {
+		t.Fatalf("event = %s, want %s", event.Type, EventDisconnected)
+	}
+	event := receiveEvent(t, ws)
+	if event.Type != EventResync {
+		t.Fatalf("event = %s, want %s", event.Type, EventResync)
+	}
+	if len(event.Subscriptions) != 1 || event.Subscriptions[0].Pair != "ltc-btc" {
+		t.Errorf("resync subscriptions = %+v", event.Subscriptions)
+	}
+
+	//the replayed subscribe delivers the next snapshot on the same channel
+	receiveBook(t, books)
+	if got := atomic.LoadInt32(conns); got != 2 {
+		t.Errorf("connections = %d, want 2", got)
+	}
+
+	if err := ws.Connect(ctx); !errors.Is(err, ErrWebsocketConnected) {
+		t.Errorf("second Connect = %v, want ErrWebsocketConnected", err)
+	}
+
+	ws.Close()
+	if _, ok := <-books; ok {
+		t.Error("order book channel still open after Close")
+	}
+}
+
+func TestWebsocketReconnectGivesUp(t *testing.T) {
+	ws, server, _ := newTestWebsocket(t, func(conn int32) bool { return true })
+	ws.Reconnect.MaxAttempts = 1
+	ctx := context.Background()
+
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	books, err := ws.SubscribeOrderBook(ctx, "binance", "ltc-btc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveBook(t, books)
+
+	//the connection drops after the snapshot and the server is gone, so the one reconnect attempt fails
+	server.Close()
+
+	select {
+	case <-ws.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("client kept reconnecting")
+	}
+
+	if _, ok := <-books; ok {
+		t.Error("order book channel still open after giving up")
+	}
+	if err := ws.Close(); err != nil {
+		t.Errorf("Close after giving up = %v", err)
+	}
+}
+
+func TestWebsocketCloseThenConnect(t *testing.T) {
+	ws, _, conns := newTestWebsocket(t, func(conn int32) bool { return false })
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		if err := ws.Connect(ctx); err != nil {
+			t.Fatalf("Connect %d = %v", i, err)
+		}
+		books, err := ws.SubscribeOrderBook(ctx, "binance", "ltc-btc")
+		if err != nil {
+			t.Fatalf("subscribe %d = %v", i, err)
+		}
+		receiveBook(t, books)
+
+		if err := ws.Close(); err != nil {
+			t.Fatalf("Close %d = %v", i, err)
+		}
+		//Close has waited for the client to stop, so nothing of this connection is left
+		select {
+		case <-ws.Done():
+		default:
+			t.Fatalf("Done still open after Close %d", i)
+		}
+		if ws.connected() {
+			t.Fatalf("still connected after Close %d", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(conns); got != 3 {
+		t.Errorf("connections = %d, want 3", got)
+	}
+}